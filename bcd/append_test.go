@@ -0,0 +1,130 @@
+package bcd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+
+	dst, err := codec.AppendEncode([]byte{0xaa}, []byte("12345"))
+	assert(err == nil)
+	assert(bytes.Equal(dst, []byte{0xaa, 0x21, 0x43, 0xf5}))
+
+	dst, err = codec.AppendEncode(dst[:1], []byte("12x45"))
+	assert(errors.Is(err, ErrBadInput))
+	assert(bytes.Equal(dst, []byte{0xaa, 0x21}))
+
+	dst, err = codec.AppendDecode([]byte("tel:"), []byte{0x21, 0x43, 0xf5})
+	assert(err == nil)
+	assert(string(dst) == "tel:12345")
+
+	buf := make([]byte, 0, 16)
+	dst, err = codec.AppendDecode(buf, []byte{0x21, 0x43, 0xf5})
+	assert(err == nil)
+	assert(&dst[0] == &buf[:1][0])
+}
+
+func TestReaderWriterAllocs(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+	src := []byte("123456789012345678901234567890123")
+	enc := make([]byte, EncodedLen(len(src)))
+	n, _ := codec.Encode(enc, src)
+	enc = enc[:n]
+
+	var dst bytes.Buffer
+	dst.Grow(len(src))
+	w := codec.NewWriter(&dst)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst.Reset()
+		w.Write(src[:10])
+		w.Write(src[10:])
+		w.Flush()
+	})
+	assert(allocs == 0)
+	assert(bytes.Equal(dst.Bytes(), enc))
+
+	in := bytes.NewReader(enc)
+	r := codec.NewReader(in)
+	p := make([]byte, 7)
+	allocs = testing.AllocsPerRun(100, func() {
+		in.Reset(enc)
+		r.Reset(in)
+		for {
+			if _, err := r.Read(p); err != nil {
+				break
+			}
+		}
+	})
+	assert(allocs == 0)
+}
+
+func ExampleEncoder_AppendEncode() {
+	enc := NewEncoder(Telephony)
+
+	dst, err := enc.AppendEncode([]byte{0x91}, []byte("12345"))
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", dst)
+	// Output: 91 21 43 f5
+}
+
+func BenchmarkAppendEncode(b *testing.B) {
+	dst := make([]byte, 0, 24)
+	enc := NewEncoder(enc)
+	src := []byte("123456789")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		dst, _ = enc.AppendEncode(dst[:0], src)
+	}
+}
+
+func BenchmarkAppendDecode(b *testing.B) {
+	dst := make([]byte, 0, 24)
+	dec := NewDecoder(enc)
+	src := []byte{0x21, 0x43, 0x65, 0x87}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		dst, _ = dec.AppendDecode(dst[:0], src)
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	w := NewEncoder(enc).NewWriter(ioutil.Discard)
+	src := []byte("1234567890123456789012345678901")
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		w.Write(src)
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	src := bytes.Repeat([]byte{0x21, 0x43, 0x65, 0x87}, 8)
+	in := bytes.NewReader(src)
+	r := NewDecoder(enc).NewReader(in)
+	p := make([]byte, 16)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		in.Reset(src)
+		r.Reset(in)
+		for {
+			if _, err := r.Read(p); err != nil {
+				break
+			}
+		}
+	}
+}
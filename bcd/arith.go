@@ -0,0 +1,190 @@
+package bcd
+
+// number is a decimal number encoded in BCD buffer. Its digits
+// occupy nibbles in the range [first, last) counted in the order of
+// encoded symbols.
+type number struct {
+	src         []byte
+	first, last int
+}
+
+// len returns the number of digits in the number.
+func (x *number) len() int {
+	return x.last - x.first
+}
+
+// nibble returns the index of k-th least significant digit.
+func (x *number) nibble(k int) int {
+	return x.last - 1 - k
+}
+
+// number validates BCD encoded decimal number in src.
+func (c *Codec) number(src []byte) (x number, err error) {
+	dec := &c.Decoder
+	n := dec.DecodedLen(src)
+	x = number{src, 0, n}
+	if dec.leading && n%2 != 0 {
+		x.first, x.last = 1, n+1
+	}
+
+	for i := x.first; i < x.last; i++ {
+		switch sym := dec.hashNib[getNibble(src, i, dec.swap)]; {
+		case sym == 0xff:
+			return x, dec.octetError(src[i/2], i/2)
+		case !isDigit(sym):
+			return x, ErrBadBCD
+		}
+	}
+	return x, nil
+}
+
+// digit returns the value of k-th least significant digit of x. The
+// digits beyond the length of x are zeros.
+func (c *Codec) digit(x *number, k int) int {
+	if k >= x.len() {
+		return 0
+	}
+	nib := getNibble(x.src, x.nibble(k), c.Decoder.swap)
+	return int(c.hashNib[nib] - '0')
+}
+
+// setDigit sets k-th least significant digit of x to d.
+func (c *Codec) setDigit(x *number, k, d int) {
+	setNibble(x.src, x.nibble(k), c.hash['0'+byte(d)], c.Encoder.swap)
+}
+
+// addTo adds y multiplied by sign to x. The digit function returns
+// k-th least significant digit of y which is ny digits long. If the
+// result is out of range x is not modified and ErrOverflow is
+// returned.
+func (c *Codec) addTo(x *number, ny int, digit func(k int) int, sign int) error {
+	n := x.len()
+	if ny > n {
+		n = ny
+	}
+
+	// the first pass detects overflow, the second one stores the
+	// result
+	for pass := 0; pass < 2; pass++ {
+		carry := 0
+		for k := 0; k < n; k++ {
+			d := c.digit(x, k) + sign*digit(k) + carry
+			carry = 0
+			if d < 0 {
+				d, carry = d+10, -1
+			} else if d > 9 {
+				d, carry = d-10, 1
+			}
+
+			if k >= x.len() {
+				if d != 0 {
+					return ErrOverflow
+				}
+			} else if pass > 0 {
+				c.setDigit(x, k, d)
+			}
+		}
+
+		if carry != 0 {
+			return ErrOverflow
+		}
+	}
+	return nil
+}
+
+func (c *Codec) addSub(x, y []byte, sign int) error {
+	a, err := c.number(x)
+	if err != nil {
+		return err
+	}
+
+	b, err := c.number(y)
+	if err != nil {
+		return err
+	}
+
+	return c.addTo(&a, b.len(), func(k int) int {
+		return c.digit(&b, k)
+	}, sign)
+}
+
+// Add adds BCD encoded unsigned decimal number y to x in place. The
+// numbers may have different number of digits. If the sum does not
+// fit into the digits of x, ErrOverflow is returned and x is left
+// intact. The filler nibble in x is preserved.
+//
+// The numbers should consist of decimal digits encoded according to
+// the configuration of c, e.g. as produced by EncodeUint64.
+func (c *Codec) Add(x, y []byte) error {
+	return c.addSub(x, y, 1)
+}
+
+// Sub subtracts BCD encoded unsigned decimal number y from x in
+// place. If y is greater than x, ErrOverflow is returned and x is
+// left intact. See Add for details.
+func (c *Codec) Sub(x, y []byte) error {
+	return c.addSub(x, y, -1)
+}
+
+// Increment adds 1 to BCD encoded unsigned decimal number x in place.
+// If x consists of nines only, ErrOverflow is returned and x is left
+// intact. See Add for details.
+func (c *Codec) Increment(x []byte) error {
+	a, err := c.number(x)
+	if err != nil {
+		return err
+	}
+
+	return c.addTo(&a, 1, func(k int) int {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}, 1)
+}
+
+// Cmp compares BCD encoded unsigned decimal numbers x and y and
+// returns -1, 0 or +1 if x is less than, equal to or greater than y
+// respectively. The numbers may have different number of digits.
+func (c *Codec) Cmp(x, y []byte) (int, error) {
+	a, err := c.number(x)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := c.number(y)
+	if err != nil {
+		return 0, err
+	}
+
+	n := a.len()
+	if b.len() > n {
+		n = b.len()
+	}
+
+	for k := n - 1; k >= 0; k-- {
+		da, db := c.digit(&a, k), c.digit(&b, k)
+		if da < db {
+			return -1, nil
+		} else if da > db {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// IsZero tells if BCD encoded unsigned decimal number x is zero.
+// Empty input is considered zero.
+func (c *Codec) IsZero(x []byte) (bool, error) {
+	a, err := c.number(x)
+	if err != nil {
+		return false, err
+	}
+
+	for k := 0; k < a.len(); k++ {
+		if c.digit(&a, k) != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
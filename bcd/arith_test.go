@@ -0,0 +1,113 @@
+package bcd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func encodeUint64(c *Codec, x uint64, digits int) []byte {
+	dst := make([]byte, EncodedLen(digits))
+	n, err := c.EncodeUint64(dst, x, digits)
+	if err != nil {
+		panic(err)
+	}
+	return dst[:n]
+}
+
+func TestArith(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, config := range []*BCD{Standard, Excess3, Aiken, Telephony, leading} {
+		c := NewCodec(config)
+		for _, test := range []struct {
+			x, y   uint64
+			nx, ny int
+		}{
+			{0, 0, 1, 1},
+			{1234, 99, 6, 2},
+			{99, 1, 3, 1},
+			{999, 1, 5, 7},
+			{123456789, 987654321, 10, 9},
+			{5, 123, 4, 3},
+		} {
+			x := encodeUint64(c, test.x, test.nx)
+			y := encodeUint64(c, test.y, test.ny)
+
+			r, err := c.Cmp(x, y)
+			assert(err == nil)
+			switch {
+			case test.x < test.y:
+				assert(r == -1)
+			case test.x > test.y:
+				assert(r == 1)
+			default:
+				assert(r == 0)
+			}
+
+			assert(c.Add(x, y) == nil)
+			v, err := c.DecodeUint64(x)
+			assert(err == nil && v == test.x+test.y)
+
+			assert(c.Sub(x, y) == nil)
+			v, _ = c.DecodeUint64(x)
+			assert(v == test.x)
+
+			if test.x < test.y {
+				assert(c.Sub(x, y) == ErrOverflow)
+				v, _ = c.DecodeUint64(x)
+				assert(v == test.x)
+			}
+
+			assert(c.Increment(x) == nil)
+			v, _ = c.DecodeUint64(x)
+			assert(v == test.x+1)
+
+			zero, err := c.IsZero(y)
+			assert(err == nil && zero == (test.y == 0))
+		}
+	}
+}
+
+func TestArithOverflow(t *testing.T) {
+	assert := newAssert(t, false)
+	c := NewCodec(Telephony)
+
+	x := encodeUint64(c, 999, 3)
+	orig := string(x)
+	assert(c.Increment(x) == ErrOverflow)
+	assert(string(x) == orig)
+
+	assert(c.Add(x, encodeUint64(c, 1, 5)) == ErrOverflow)
+	assert(string(x) == orig)
+
+	x = encodeUint64(c, 10, 2)
+	assert(c.Add(x, encodeUint64(c, 5, 5)) == nil)
+	v, _ := c.DecodeUint64(x)
+	assert(v == 15)
+
+	assert(c.Sub(x, encodeUint64(c, 16, 2)) == ErrOverflow)
+	v, _ = c.DecodeUint64(x)
+	assert(v == 15)
+
+	_, err := c.Cmp(x, []byte{0x21, 0xf3, 0x54})
+	assert(errors.Is(err, ErrBadBCD))
+
+	_, err = c.IsZero([]byte{0xa1})
+	assert(err == ErrBadBCD)
+
+	zero, err := c.IsZero(nil)
+	assert(err == nil && zero)
+}
+
+func ExampleCodec_Increment() {
+	c := NewCodec(Standard)
+	counter := []byte{0x00, 0x19, 0x99}
+
+	if err := c.Increment(counter); err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", counter)
+	// Output: 00 20 00
+}
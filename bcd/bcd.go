@@ -25,6 +25,18 @@ type BCD struct {
 	// number of bytes. Then the output's final nibble
 	// will contain the specified nibble.
 	Filler byte
+
+	// If true the filler nibble is placed at the beginning of the
+	// output instead of its end, i.e. the odd number of digits is
+	// right-justified. For example, "123" is encoded as 0xf1 0x23
+	// instead of 0x12 0x3f.
+	LeadingFiller bool
+
+	// Aliases of symbols which are encoded as the symbols they
+	// refer to. Decoding always yields the symbols of Map.
+	// Example:
+	//    key 'A' -> value 'a'
+	Aliases map[byte]byte
 }
 
 var (
@@ -57,7 +69,10 @@ var (
 			'a': 0xc, 'b': 0xd, 'c': 0xe,
 		},
 		SwapNibbles: true,
-		Filler:      0xf}
+		Filler:      0xf,
+		Aliases: map[byte]byte{
+			'A': 'a', 'B': 'b', 'C': 'c',
+		}}
 
 	// Aiken or 2421 code
 	Aiken = &BCD{
@@ -68,6 +83,36 @@ var (
 		},
 		SwapNibbles: false,
 		Filler:      0x5}
+
+	// 5421 code.
+	Code5421 = &BCD{
+		Map: map[byte]byte{
+			'0': 0x0, '1': 0x1, '2': 0x2, '3': 0x3,
+			'4': 0x4, '5': 0x8, '6': 0x9, '7': 0xa,
+			'8': 0xb, '9': 0xc,
+		},
+		SwapNibbles: false,
+		Filler:      0xf}
+
+	// Self-complementing 4221 code.
+	Code4221 = &BCD{
+		Map: map[byte]byte{
+			'0': 0x0, '1': 0x1, '2': 0x2, '3': 0x3,
+			'4': 0x8, '5': 0x7, '6': 0xc, '7': 0xd,
+			'8': 0xe, '9': 0xf,
+		},
+		SwapNibbles: false,
+		Filler:      0x4}
+
+	// Gray BCD where adjacent digits differ in one bit.
+	Gray = &BCD{
+		Map: map[byte]byte{
+			'0': 0x0, '1': 0x1, '2': 0x3, '3': 0x2,
+			'4': 0x6, '5': 0x7, '6': 0x5, '7': 0x4,
+			'8': 0xc, '9': 0xd,
+		},
+		SwapNibbles: false,
+		Filler:      0xf}
 )
 
 // Error values returned by API.
@@ -76,4 +121,75 @@ var (
 	ErrBadInput = fmt.Errorf("non-encodable data")
 	// ErrBadBCD returned if input data cannot be decoded.
 	ErrBadBCD = fmt.Errorf("Bad BCD data")
+	// ErrOverflow returned if the value does not fit into the
+	// specified number of digits.
+	ErrOverflow = fmt.Errorf("value overflow")
+)
+
+// Reason tells why the data cannot be encoded or decoded.
+type Reason int
+
+const (
+	// UnmappedSymbol means the input symbol has no nibble in the
+	// table.
+	UnmappedSymbol Reason = iota + 1
+	// UnmappedNibble means the nibble has no symbol in the table.
+	UnmappedNibble
+	// UnexpectedFiller means the filler nibble was found where it's
+	// not allowed.
+	UnexpectedFiller
 )
+
+func (r Reason) String() string {
+	switch r {
+	case UnmappedSymbol:
+		return "unmapped symbol"
+	case UnmappedNibble:
+		return "unmapped nibble"
+	case UnexpectedFiller:
+		return "unexpected filler"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// Error describes the position and the cause of encoding or decoding
+// failure. Error wraps ErrBadInput or ErrBadBCD so it can be matched
+// with errors.Is.
+type Error struct {
+	// Offset of the offending byte in the input. Reader and Writer
+	// count it from the beginning of the stream.
+	Offset int
+
+	// The offending byte: the symbol if encoding, or the octet if
+	// decoding.
+	Byte byte
+
+	// If true the offending nibble is the high one (bits 4-7) of the
+	// octet. Not used if encoding.
+	High bool
+
+	// Reason of the failure.
+	Reason Reason
+
+	// Err is either ErrBadInput or ErrBadBCD.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err == ErrBadInput {
+		return fmt.Sprintf("%v: %v %q at offset %d",
+			e.Err, e.Reason, e.Byte, e.Offset)
+	}
+
+	half := "low"
+	if e.High {
+		half = "high"
+	}
+	return fmt.Sprintf("%v: %v in %s half of 0x%02x at offset %d",
+		e.Err, e.Reason, half, e.Byte, e.Offset)
+}
+
+// Unwrap returns the underlying error value.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -2,6 +2,7 @@ package bcd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"testing"
@@ -51,7 +52,7 @@ func TestEncodeOpt(t *testing.T) {
 
 	input = "unacceptable"
 	n, err = enc.Encode(output, []byte(input))
-	assert(err == ErrBadInput)
+	assert(errors.Is(err, ErrBadInput))
 }
 
 func ExampleEncoder_Encode() {
@@ -123,13 +124,13 @@ func TestDecodeOpt(t *testing.T) {
 
 	input = []byte{0xff, 0xff}
 	n, err = enc.Decode(output, input)
-	assert(err == ErrBadBCD)
+	assert(errors.Is(err, ErrBadBCD))
 
 	// try not ignoring the fillers
 	input = []byte{0x21, 0xf3, 0xf4, 0x65, 0xf7}
 	enc.IgnoreFiller = false
 	n, err = enc.Decode(output, input)
-	assert(err == ErrBadBCD)
+	assert(errors.Is(err, ErrBadBCD))
 
 	// try ignoring the fillers
 	enc.IgnoreFiller = true
@@ -149,7 +150,7 @@ func testDecodeReader(t *testing.T, srcS []byte, dstS string, expected bool) {
 		assert(dst.String() == dstS)
 		assert(err == nil)
 	} else {
-		assert(err == ErrBadBCD)
+		assert(errors.Is(err, ErrBadBCD))
 	}
 
 	dst.Reset()
@@ -160,7 +161,7 @@ func testDecodeReader(t *testing.T, srcS []byte, dstS string, expected bool) {
 		assert(dst.String() == dstS)
 		assert(err == nil)
 	} else {
-		assert(err == ErrBadBCD)
+		assert(errors.Is(err, ErrBadBCD))
 	}
 }
 
@@ -252,3 +253,133 @@ func BenchmarkDecodeOpt(b *testing.B) {
 		enc.Decode(out, []byte{0x21, 0x43, 0x65, 0x87})
 	}
 }
+
+var (
+	// right-justified Standard
+	leading = &BCD{
+		Map:           Standard.Map,
+		Filler:        0xf,
+		LeadingFiller: true}
+)
+
+func TestLeadingFiller(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(leading)
+	output := make([]byte, 10)
+
+	n, err := codec.Encode(output, []byte("123"))
+	assert(err == nil)
+	assert(bytes.Equal(output[:n], []byte{0xf1, 0x23}))
+
+	n, err = codec.Encode(output, []byte("1234"))
+	assert(err == nil)
+	assert(bytes.Equal(output[:n], []byte{0x12, 0x34}))
+
+	n, err = codec.EncodeUint64(output, 123, 5)
+	assert(err == nil)
+	assert(bytes.Equal(output[:n], []byte{0xf0, 0x01, 0x23}))
+
+	zero := NewEncoder(&BCD{Map: Standard.Map, LeadingFiller: true})
+	n, err = zero.Encode(output, []byte("123"))
+	assert(err == nil)
+	assert(bytes.Equal(output[:n], []byte{0x01, 0x23}))
+
+	src := []byte{0xf1, 0x23}
+	assert(codec.DecodedLen(src) == 3)
+	n, err = codec.Decode(output, src)
+	assert(err == nil)
+	assert(string(output[:n]) == "123")
+
+	x, err := codec.DecodeUint64(src)
+	assert(err == nil)
+	assert(x == 123)
+
+	src = []byte{0x12, 0xf3}
+	assert(codec.DecodedLen(src) == 4)
+	_, err = codec.Decode(output, src)
+	assert(errors.Is(err, ErrBadBCD))
+
+	swapped := NewCodec(&BCD{
+		Map:           Telephony.Map,
+		SwapNibbles:   true,
+		Filler:        0xf,
+		LeadingFiller: true})
+	n, err = swapped.Encode(output, []byte("123"))
+	assert(err == nil)
+	assert(bytes.Equal(output[:n], []byte{0x1f, 0x32}))
+
+	n, err = swapped.Decode(output, []byte{0x1f, 0x32})
+	assert(err == nil)
+	assert(string(output[:n]) == "123")
+}
+
+func TestLeadingFillerReaderWriter(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, s := range []string{"1", "12", "12345", "123456"} {
+		dst := new(bytes.Buffer)
+		w := NewEncoder(leading).NewWriter(dst)
+		_, err := io.Copy(w, iotest.OneByteReader(bytes.NewBufferString(s)))
+		assert(err == nil)
+		assert(w.Buffered() == len(s))
+		assert(w.Flush() == nil)
+
+		enc := make([]byte, EncodedLen(len(s)))
+		n, _ := NewEncoder(leading).Encode(enc, []byte(s))
+		assert(bytes.Equal(dst.Bytes(), enc[:n]))
+
+		out := new(bytes.Buffer)
+		r := NewDecoder(leading).NewReader(dst)
+		_, err = io.Copy(out, iotest.OneByteReader(r))
+		assert(err == nil)
+		assert(out.String() == s)
+	}
+
+	r := NewDecoder(leading).NewReader(bytes.NewReader([]byte{0x12, 0xf3}))
+	_, err := io.Copy(new(bytes.Buffer), r)
+	assert(errors.Is(err, ErrBadBCD))
+
+	w := NewEncoder(leading).NewWriter(new(bytes.Buffer))
+	n, err := w.Write([]byte("12x4"))
+	assert(errors.Is(err, ErrBadInput))
+	assert(n == 2)
+}
+
+func TestError(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+	output := make([]byte, 20)
+
+	_, err := codec.Encode(output, []byte("1234x6"))
+	e, ok := err.(*Error)
+	assert(ok && errors.Is(err, ErrBadInput))
+	assert(e.Offset == 4 && e.Byte == 'x' && e.Reason == UnmappedSymbol)
+
+	_, err = codec.Decode(output, []byte{0x21, 0x43, 0xf5, 0x87})
+	e, ok = err.(*Error)
+	assert(ok && errors.Is(err, ErrBadBCD))
+	assert(e.Offset == 2 && e.Byte == 0xf5 && e.High && e.Reason == UnexpectedFiller)
+
+	_, err = codec.Decode(output, []byte{0x21, 0x5f, 0x87})
+	e, ok = err.(*Error)
+	assert(ok && errors.Is(err, ErrBadBCD))
+	assert(e.Offset == 1 && !e.High && e.Reason == UnexpectedFiller)
+
+	_, err = NewDecoder(Standard).Decode(output, []byte{0x12, 0x3a})
+	e, ok = err.(*Error)
+	assert(ok && errors.Is(err, ErrBadBCD))
+	assert(e.Offset == 1 && !e.High && e.Reason == UnmappedNibble)
+	assert(e.Error() == "Bad BCD data: unmapped nibble in low half of 0x3a at offset 1")
+
+	r := codec.NewReader(bytes.NewReader([]byte{0x21, 0x43, 0x65, 0xf7, 0x98}))
+	_, err = io.Copy(new(bytes.Buffer), iotest.OneByteReader(r))
+	e, ok = err.(*Error)
+	assert(ok && e.Offset == 3 && e.Reason == UnexpectedFiller)
+
+	w := codec.NewWriter(new(bytes.Buffer))
+	_, err = w.Write([]byte("123"))
+	assert(err == nil)
+	_, err = w.Write([]byte("45#*y"))
+	e, ok = err.(*Error)
+	assert(ok && e.Offset == 7 && e.Byte == 'y')
+}
@@ -0,0 +1,258 @@
+/*
+Package bcdtime converts BCD encoded date and time fields to and from
+time.Time. It supports packed BCD dates as found in ISO 8583 messages
+and smart-card records, and 3GPP Service Centre Time Stamp (SCTS) as
+in 3GPP TS 23.040.
+*/
+package bcdtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+// Layouts of packed BCD date and time fields. Layout is a sequence
+// of the following elements:
+//
+//	YYYY - year, 4 digits
+//	YY   - year, 2 digits; 69-99 stand for 1969-1999, 00-68 stand
+//	       for 2000-2068
+//	MM   - month, 01-12
+//	DD   - day of month, 01-31
+//	hh   - hour, 00-23
+//	mm   - minute, 00-59
+//	ss   - second, 00-59
+//
+// Every element occupies exactly one octet except for YYYY which
+// occupies two.
+const (
+	// Date, e.g. ISO 8583 field 13 with the year.
+	LayoutDate = "YYMMDD"
+	// Date with 4-digit year.
+	LayoutLongDate = "YYYYMMDD"
+	// Date and time.
+	LayoutDateTime = "YYMMDDhhmmss"
+	// Time, e.g. ISO 8583 field 12.
+	LayoutTime = "hhmmss"
+	// Transmission date and time, ISO 8583 field 7.
+	LayoutTransmission = "MMDDhhmmss"
+	// Expiration date, ISO 8583 field 14.
+	LayoutExpiry = "YYMM"
+)
+
+// Error values returned by API.
+var (
+	// ErrLayout returned if the layout is invalid.
+	ErrLayout = fmt.Errorf("invalid layout")
+	// ErrRange returned if the field value is out of range.
+	ErrRange = fmt.Errorf("value out of range")
+)
+
+var (
+	codec = bcd.NewCodec(bcd.Standard)
+	// semi-octets as in 3GPP TS 23.040
+	semiOctets = bcd.NewCodec(&bcd.BCD{
+		Map:         bcd.Standard.Map,
+		SwapNibbles: true,
+		Filler:      0xf})
+)
+
+var elements = []string{"YYYY", "YY", "MM", "DD", "hh", "mm", "ss"}
+
+// element returns the layout element at the beginning of layout.
+func element(layout string) string {
+	for _, e := range elements {
+		if strings.HasPrefix(layout, e) {
+			return e
+		}
+	}
+	return ""
+}
+
+// EncodedLen returns the length of encoded field of the layout or
+// -1 if the layout is invalid.
+func EncodedLen(layout string) int {
+	n := 0
+	for len(layout) > 0 {
+		e := element(layout)
+		if e == "" {
+			return -1
+		}
+		n += len(e) / 2
+		layout = layout[len(e):]
+	}
+	return n
+}
+
+func atoi(s []byte) (n int) {
+	for _, c := range s {
+		n = n*10 + int(c-'0')
+	}
+	return
+}
+
+func checkRange(name string, x, min, max int) error {
+	if x < min || x > max {
+		return fmt.Errorf("%s %d: %w", name, x, ErrRange)
+	}
+	return nil
+}
+
+// daysIn returns the number of days in month of year. If year is not
+// known February has 29 days.
+func daysIn(month, year int, known bool) int {
+	if month == 2 && !known {
+		return 29
+	}
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// date is a broken down time.
+type date struct {
+	year, month, day int
+	hour, min, sec   int
+	knownYear        bool
+}
+
+func (d *date) validate() error {
+	if err := checkRange("month", d.month, 1, 12); err != nil {
+		return err
+	}
+	if err := checkRange("day", d.day, 1, daysIn(d.month, d.year, d.knownYear)); err != nil {
+		return err
+	}
+	if err := checkRange("hour", d.hour, 0, 23); err != nil {
+		return err
+	}
+	if err := checkRange("minute", d.min, 0, 59); err != nil {
+		return err
+	}
+	return checkRange("second", d.sec, 0, 59)
+}
+
+func (d *date) time(loc *time.Location) time.Time {
+	return time.Date(d.year, time.Month(d.month), d.day,
+		d.hour, d.min, d.sec, 0, loc)
+}
+
+// shortYear returns 4-digit year of 2-digit year.
+func shortYear(yy int) int {
+	if yy >= 69 {
+		return 1900 + yy
+	}
+	return 2000 + yy
+}
+
+// decodeDigits decodes src into decimal digits.
+func decodeDigits(dec *bcd.Decoder, src []byte) ([]byte, error) {
+	digits := make([]byte, bcd.DecodedLen(len(src)))
+	n, err := dec.Decode(digits, src)
+	if err != nil {
+		return nil, err
+	}
+
+	digits = digits[:n]
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, bcd.ErrBadBCD
+		}
+	}
+	return digits, nil
+}
+
+// Decode decodes packed BCD date and time field of specified layout
+// from src. Elements missing from the layout are set to their
+// minimum, i.e. year 0, January, first day of month, midnight. The
+// time is in UTC.
+func Decode(layout string, src []byte) (time.Time, error) {
+	return DecodeInLocation(layout, src, time.UTC)
+}
+
+// DecodeInLocation is like Decode but the time is in specified
+// location.
+func DecodeInLocation(layout string, src []byte, loc *time.Location) (time.Time, error) {
+	n := EncodedLen(layout)
+	if n < 0 {
+		return time.Time{}, ErrLayout
+	}
+
+	if len(src) < n {
+		return time.Time{}, bcd.ErrBadBCD
+	}
+
+	digits, err := decodeDigits(&codec.Decoder, src[:n])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	d := date{month: 1, day: 1}
+	for len(layout) > 0 {
+		e := element(layout)
+		x := atoi(digits[:len(e)])
+		switch e {
+		case "YYYY":
+			d.year, d.knownYear = x, true
+		case "YY":
+			d.year, d.knownYear = shortYear(x), true
+		case "MM":
+			d.month = x
+		case "DD":
+			d.day = x
+		case "hh":
+			d.hour = x
+		case "mm":
+			d.min = x
+		case "ss":
+			d.sec = x
+		}
+		layout, digits = layout[len(e):], digits[len(e):]
+	}
+
+	if err := d.validate(); err != nil {
+		return time.Time{}, err
+	}
+	return d.time(loc), nil
+}
+
+// Append encodes t into packed BCD date and time field of specified
+// layout and appends it to dst. Elements missing from the layout are
+// not encoded. The extended slice and possible error is returned.
+func Append(dst []byte, layout string, t time.Time) ([]byte, error) {
+	if EncodedLen(layout) < 0 {
+		return dst, ErrLayout
+	}
+
+	digits := make([]byte, 0, 2*EncodedLen(layout))
+	for len(layout) > 0 {
+		e := element(layout)
+		var x int
+		switch e {
+		case "YYYY":
+			if x = t.Year(); x < 0 || x > 9999 {
+				return dst, fmt.Errorf("year %d: %w", x, ErrRange)
+			}
+		case "YY":
+			if x = t.Year(); shortYear(x%100) != x {
+				return dst, fmt.Errorf("year %d: %w", x, ErrRange)
+			}
+			x %= 100
+		case "MM":
+			x = int(t.Month())
+		case "DD":
+			x = t.Day()
+		case "hh":
+			x = t.Hour()
+		case "mm":
+			x = t.Minute()
+		case "ss":
+			x = t.Second()
+		}
+		digits = append(digits, fmt.Sprintf("%0*d", len(e), x)...)
+		layout = layout[len(e):]
+	}
+
+	return codec.AppendEncode(dst, digits)
+}
@@ -0,0 +1,123 @@
+package bcdtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+func assert(t testing.TB, expected bool, args ...interface{}) {
+	if !expected {
+		t.Helper()
+		t.Fatal(args...)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tm, err := Decode(LayoutDateTime, []byte{0x21, 0x03, 0x15, 0x13, 0x45, 0x59})
+	assert(t, err == nil, err)
+	assert(t, tm.Equal(time.Date(2021, 3, 15, 13, 45, 59, 0, time.UTC)), tm)
+
+	tm, err = Decode(LayoutLongDate, []byte{0x19, 0x96, 0x02, 0x29})
+	assert(t, err == nil, err)
+	assert(t, tm.Equal(time.Date(1996, 2, 29, 0, 0, 0, 0, time.UTC)), tm)
+
+	tm, err = Decode(LayoutTransmission, []byte{0x02, 0x29, 0x23, 0x59, 0x00})
+	assert(t, err == nil, err)
+	assert(t, tm.Month() == 2 && tm.Day() == 29 && tm.Hour() == 23)
+
+	tm, err = Decode(LayoutExpiry, []byte{0x99, 0x12})
+	assert(t, err == nil, err)
+	assert(t, tm.Equal(time.Date(1999, 12, 1, 0, 0, 0, 0, time.UTC)), tm)
+
+	_, err = Decode(LayoutLongDate, []byte{0x19, 0x97, 0x02, 0x29})
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Decode(LayoutTime, []byte{0x24, 0x00, 0x00})
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Decode(LayoutTime, []byte{0x23, 0x0a, 0x00})
+	assert(t, errors.Is(err, bcd.ErrBadBCD), err)
+
+	_, err = Decode(LayoutTime, []byte{0x23, 0x00})
+	assert(t, errors.Is(err, bcd.ErrBadBCD), err)
+
+	_, err = Decode("YYMMDDx", []byte{0x21, 0x01, 0x01, 0x00})
+	assert(t, err == ErrLayout, err)
+}
+
+func TestAppend(t *testing.T) {
+	tm := time.Date(2021, 3, 15, 13, 45, 59, 0, time.UTC)
+
+	data, err := Append([]byte{0xaa}, LayoutDateTime, tm)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data, []byte{0xaa, 0x21, 0x03, 0x15, 0x13, 0x45, 0x59}), data)
+
+	data, err = Append(nil, LayoutLongDate, tm)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data, []byte{0x20, 0x21, 0x03, 0x15}), data)
+
+	_, err = Append(nil, LayoutDate, time.Date(2077, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Append(nil, "YYYYMMD", tm)
+	assert(t, err == ErrLayout, err)
+}
+
+func TestSCTS(t *testing.T) {
+	src := []byte{0x12, 0x30, 0x51, 0x31, 0x54, 0x95, 0x80}
+	tm, err := DecodeSCTS(src)
+	assert(t, err == nil, err)
+	_, offset := tm.Zone()
+	assert(t, offset == 2*3600, offset)
+	assert(t, tm.Equal(time.Date(2021, 3, 15, 11, 45, 59, 0, time.UTC)), tm)
+
+	data, err := AppendSCTS(nil, tm)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data, src), data)
+
+	// negative time zone: -5:30
+	src[6] = 0x2a
+	tm, err = DecodeSCTS(src)
+	assert(t, err == nil, err)
+	_, offset = tm.Zone()
+	assert(t, offset == -(5*3600+30*60), offset)
+
+	data, err = AppendSCTS(nil, tm)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data, src), data)
+
+	src[6] = 0x07
+	_, err = DecodeSCTS(src)
+	assert(t, errors.Is(err, ErrRange), err)
+
+	src[6] = 0x00
+	src[1] = 0x31
+	_, err = DecodeSCTS(src)
+	assert(t, errors.Is(err, ErrRange), err)
+
+	// filler nibble in the time zone octet
+	src[1] = 0x30
+	for _, tz := range []byte{0xf0, 0xf8, 0xf2} {
+		src[6] = tz
+		_, err = DecodeSCTS(src)
+		assert(t, errors.Is(err, bcd.ErrBadBCD), tz, err)
+	}
+
+	_, err = AppendSCTS(nil, tm.In(time.FixedZone("", 10*60)))
+	assert(t, errors.Is(err, ErrRange), err)
+}
+
+func ExampleDecodeSCTS() {
+	tm, err := DecodeSCTS([]byte{0x12, 0x30, 0x51, 0x31, 0x54, 0x95, 0x88})
+	if err != nil {
+		return
+	}
+
+	fmt.Println(tm.Format(time.RFC3339))
+	// Output: 2021-03-15T13:45:59-02:00
+}
@@ -0,0 +1,103 @@
+package bcdtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+// SCTSLen is the length of encoded Service Centre Time Stamp.
+const SCTSLen = 7
+
+// maximum time zone offset in quarters of an hour
+const maxQuarters = 14 * 4
+
+// DecodeSCTS decodes Service Centre Time Stamp as in 3GPP TS 23.040
+// clause 9.2.3.11 from src. The time stamp consists of year, month,
+// day, hour, minute, second and time zone octets in semi-octet
+// representation. The time zone is the signed difference between
+// the local time and GMT in quarters of an hour. The time is located
+// in the fixed zone of that offset.
+func DecodeSCTS(src []byte) (time.Time, error) {
+	if len(src) < SCTSLen {
+		return time.Time{}, bcd.ErrBadBCD
+	}
+
+	// sign is the bit 3 of the time zone octet
+	tz := src[6]
+	neg := tz&0x08 != 0
+
+	var buf [SCTSLen]byte
+	copy(buf[:], src[:SCTSLen])
+	buf[6] = tz &^ 0x08
+
+	digits, err := decodeDigits(&semiOctets.Decoder, buf[:])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(digits) != 2*SCTSLen {
+		// filler nibble in the time stamp
+		return time.Time{}, bcd.ErrBadBCD
+	}
+
+	d := date{
+		year:      shortYear(atoi(digits[0:2])),
+		month:     atoi(digits[2:4]),
+		day:       atoi(digits[4:6]),
+		hour:      atoi(digits[6:8]),
+		min:       atoi(digits[8:10]),
+		sec:       atoi(digits[10:12]),
+		knownYear: true,
+	}
+
+	if err := d.validate(); err != nil {
+		return time.Time{}, err
+	}
+
+	q := atoi(digits[12:14])
+	if err := checkRange("time zone", q, 0, maxQuarters); err != nil {
+		return time.Time{}, err
+	}
+	if neg {
+		q = -q
+	}
+
+	return d.time(time.FixedZone("", q*15*60)), nil
+}
+
+// AppendSCTS encodes t into Service Centre Time Stamp and appends it
+// to dst. The zone offset of t should be a multiple of 15 minutes.
+// The extended slice and possible error is returned.
+func AppendSCTS(dst []byte, t time.Time) ([]byte, error) {
+	year := t.Year()
+	if shortYear(year%100) != year {
+		return dst, fmt.Errorf("year %d: %w", year, ErrRange)
+	}
+
+	_, offset := t.Zone()
+	q := offset / (15 * 60)
+	if q*15*60 != offset || q > maxQuarters || q < -maxQuarters {
+		return dst, fmt.Errorf("time zone offset %d: %w", offset, ErrRange)
+	}
+
+	neg := q < 0
+	if neg {
+		q = -q
+	}
+
+	digits := fmt.Sprintf("%02d%02d%02d%02d%02d%02d%02d", year%100,
+		t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), q)
+
+	n := len(dst)
+	dst, err := semiOctets.AppendEncode(dst, []byte(digits))
+	if err != nil {
+		return dst[:n], err
+	}
+
+	if neg {
+		dst[n+6] |= 0x08
+	}
+	return dst, nil
+}
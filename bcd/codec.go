@@ -1,6 +1,7 @@
 package bcd
 
-// Codec encapsulates both Encoder and Decoder.
+// Codec encapsulates both Encoder and Decoder. Use its Encoder or
+// Decoder field as transform.Transformer.
 type Codec struct {
 	Encoder
 	Decoder
@@ -1,5 +1,9 @@
 package bcd
 
+import (
+	"encoding/binary"
+)
+
 type word [2]byte
 type dword [4]byte
 type qword [8]byte
@@ -13,16 +17,42 @@ type Decoder struct {
 	// resume decoding quietly in that case by setting this.
 	IgnoreFiller bool
 
+	// Placeholder is the symbol which DecodeLossy substitutes for
+	// unmapped nibbles. If zero, '?' is used.
+	Placeholder byte
+
+	// FillerMark is the symbol which DecodeLossy substitutes for
+	// unexpected filler nibbles. If zero, '_' is used.
+	FillerMark byte
+
 	// two nibbles (1 byte) to 2 symbols mapping; example: 0x45 ->
 	// '45' or '54' depending on nibble swapping additional 2 bytes of
 	// dword should be 0, otherwise given byte is unacceptable
 	hashWord [0x100]dword
 
-	// one finishing byte with filler nibble to 1 symbol mapping;
-	// example: 0x4f -> '4' (filler=0xf, swap=false)
+	// one finishing (or starting if filler is leading) byte with
+	// filler nibble to 1 symbol mapping; example: 0x4f -> '4'
+	// (filler=0xf, swap=false)
 	// additional byte of word should 0, otherise given nibble is
 	// unacceptable
 	hashByte [0x100]word
+
+	// nibble to symbol mapping; the value 0xff means no mapping
+	hashNib [0x10]byte
+
+	// two nibbles (1 byte) to 2 symbols mapping packed into lower 16
+	// bits in little endian order; wideInvalid bit is set if given
+	// byte is unacceptable
+	hashWide [0x100]uint32
+
+	// nibble used to fill if the number of bytes is odd
+	filler byte
+
+	// if true the 0x45 translates to '54' and vice versa
+	swap bool
+
+	// if true the filler nibble is expected first
+	leading bool
 }
 
 func newHashDecWord(config *BCD) (res [0x100]dword) {
@@ -47,6 +77,20 @@ func newHashDecWord(config *BCD) (res [0x100]dword) {
 	return
 }
 
+// wideInvalid marks unacceptable byte in hashWide.
+const wideInvalid = 1 << 16
+
+func newHashDecWide(hashWord *[0x100]dword) (res [0x100]uint32) {
+	for i, dw := range hashWord {
+		if dw[2] != 0 {
+			res[i] = wideInvalid
+		} else {
+			res[i] = uint32(dw[0]) | uint32(dw[1])<<8
+		}
+	}
+	return
+}
+
 func newHashDecByte(config *BCD) (res [0x100]word) {
 	var b byte
 	for i, _ := range res {
@@ -54,7 +98,7 @@ func newHashDecByte(config *BCD) (res [0x100]word) {
 		res[i] = word{0xff, 0xff}
 	}
 	for c, nib := range config.Map {
-		if config.SwapNibbles {
+		if config.SwapNibbles != config.LeadingFiller {
 			b = (config.Filler << 4) + nib&0xf
 		} else {
 			b = (nib << 4) + config.Filler&0xf
@@ -64,6 +108,24 @@ func newHashDecByte(config *BCD) (res [0x100]word) {
 	return
 }
 
+func newHashDecNibble(config *BCD) (res [0x10]byte) {
+	for i := range res {
+		// invalidating all nibbles by default
+		res[i] = 0xff
+	}
+	for c, nib := range config.Map {
+		res[nib] = c
+	}
+	return
+}
+
+func (dec *Decoder) unpackNibs(b byte) (nib1, nib2 byte) {
+	if dec.swap {
+		return b & 0xf, b >> 4
+	}
+	return b >> 4, b & 0xf
+}
+
 func (dec *Decoder) unpack(w []byte, b byte) (n int, end bool, err error) {
 	if dw := dec.hashWord[b]; dw[2] == 0 {
 		return copy(w, dw[:2]), false, nil
@@ -74,6 +136,39 @@ func (dec *Decoder) unpack(w []byte, b byte) (n int, end bool, err error) {
 	return 0, false, ErrBadBCD
 }
 
+// octetError returns the error describing why octet b found at
+// offset off of the input cannot be decoded. If the octet itself is
+// valid the filler nibble in it is treated as unexpected.
+func (dec *Decoder) octetError(b byte, off int) error {
+	var nibs [2]byte
+	nibs[0], nibs[1] = dec.unpackNibs(b)
+
+	// filler is legitimate in this nibble of an octet
+	legal := 1
+	if dec.leading {
+		legal = 0
+	}
+
+	idx, reason := legal, UnexpectedFiller
+	for i, nib := range nibs {
+		if dec.hashNib[nib] != 0xff || (nib == dec.filler && i == legal) {
+			continue
+		}
+		if nib != dec.filler {
+			reason = UnmappedNibble
+		}
+		idx = i
+		break
+	}
+
+	return &Error{
+		Offset: off,
+		Byte:   b,
+		High:   (idx == 0) != dec.swap,
+		Reason: reason,
+		Err:    ErrBadBCD}
+}
+
 // NewDecoder creates new Decoder from BCD configuration. If the
 // configuration is invalid NewDecoder will panic.
 func NewDecoder(config *BCD) *Decoder {
@@ -81,9 +176,15 @@ func NewDecoder(config *BCD) *Decoder {
 		panic("BCD table is incorrect")
 	}
 
-	return &Decoder{
+	dec := &Decoder{
 		hashWord: newHashDecWord(config),
-		hashByte: newHashDecByte(config)}
+		hashByte: newHashDecByte(config),
+		hashNib:  newHashDecNibble(config),
+		filler:   config.Filler,
+		swap:     config.SwapNibbles,
+		leading:  config.LeadingFiller}
+	dec.hashWide = newHashDecWide(&dec.hashWord)
+	return dec
 }
 
 // DecodedLen tells how much space is needed to store decoded string.
@@ -96,34 +197,109 @@ func DecodedLen(x int) int {
 	return 2 * x
 }
 
+// fillerAt returns the index of the octet which may legitimately
+// contain the filler nibble in the input of length x.
+func (dec *Decoder) fillerAt(x int) int {
+	if dec.leading {
+		return 0
+	}
+	return x - 1
+}
+
+// DecodedLen returns the exact length of decoded string for the
+// given BCD encoded input, i.e. it accounts for the filler nibble in
+// the first or the last octet depending on the configuration. The
+// input is not validated.
+func (dec *Decoder) DecodedLen(src []byte) int {
+	n := DecodedLen(len(src))
+	if len(src) == 0 {
+		return n
+	}
+
+	b := src[dec.fillerAt(len(src))]
+	if dec.hashWord[b][2] != 0 && dec.hashByte[b][1] == 0 {
+		n--
+	}
+	return n
+}
+
 // Decode parses BCD encoded bytes from src and tries to decode them
-// to dst. Number of decoded bytes and possible error is returned.
+// to dst. Number of decoded bytes and possible error is returned. The
+// error is of type *Error if src contains invalid octets.
 func (dec *Decoder) Decode(dst, src []byte) (n int, err error) {
-	if len(src) == 0 {
-		return 0, nil
+	return dec.decode(dst, src, dec.fillerAt(len(src)), 0)
+}
+
+// wideLen is the number of octets decoded in one step of the fast
+// path.
+const wideLen = 8
+
+// unpackWide decodes wideLen octets of src into dst if all of them
+// contain two symbols each. It returns false if any of the octets
+// contains the filler or invalid nibble.
+func (dec *Decoder) unpackWide(dst, src []byte) bool {
+	_, _ = dst[2*wideLen-1], src[wideLen-1]
+	h := &dec.hashWide
+	x0, x1, x2, x3 := h[src[0]], h[src[1]], h[src[2]], h[src[3]]
+	x4, x5, x6, x7 := h[src[4]], h[src[5]], h[src[6]], h[src[7]]
+	if (x0|x1|x2|x3|x4|x5|x6|x7)&wideInvalid != 0 {
+		return false
 	}
 
-	for _, c := range src[:len(src)-1] {
+	binary.LittleEndian.PutUint64(dst,
+		uint64(x0)|uint64(x1)<<16|uint64(x2)<<32|uint64(x3)<<48)
+	binary.LittleEndian.PutUint64(dst[wideLen:],
+		uint64(x4)|uint64(x5)<<16|uint64(x6)<<32|uint64(x7)<<48)
+	return true
+}
+
+// decode decodes src into dst. The filler is expected only in the
+// octet of src at index last. Offsets in errors are counted from off.
+//
+// The input is decoded in blocks of wideLen octets while there is
+// enough space in dst. The blocks containing filler or invalid
+// nibbles are decoded octet by octet.
+func (dec *Decoder) decode(dst, src []byte, last, off int) (n int, err error) {
+	i := 0
+	for ; len(src)-i >= wideLen && len(dst)-n >= 2*wideLen; i += wideLen {
+		if dec.unpackWide(dst[n:], src[i:]) {
+			n += 2 * wideLen
+			continue
+		}
+
+		m, err := dec.decodeTable(dst[n:], src[i:i+wideLen], last-i, off+i)
+		if n += m; err != nil {
+			return n, err
+		}
+	}
+
+	m, err := dec.decodeTable(dst[n:], src[i:], last-i, off+i)
+	return n + m, err
+}
+
+// decodeTable is like decode but it decodes src octet by octet.
+func (dec *Decoder) decodeTable(dst, src []byte, last, off int) (n int, err error) {
+	for i, c := range src {
 		wid, end, err := dec.unpack(dst[n:], c)
 		switch {
 		case err != nil: // invalid input
-			return n, err
+			return n, dec.octetError(c, off+i)
 		case wid == 0: // no place in dst
 			return n, nil
-		case end && !dec.IgnoreFiller: // unexpected filler
-			return n, ErrBadBCD
+		case end && i != last && !dec.IgnoreFiller: // unexpected filler
+			return n, dec.octetError(c, off+i)
 		}
 		n += wid
 	}
-
-	c := src[len(src)-1]
-	wid, _, err := dec.unpack(dst[n:], c)
-	switch {
-	case err != nil: // invalid input
-		return n, err
-	case wid == 0: // no place in dst
-		return n, nil
-	}
-	n += wid
 	return n, nil
 }
+
+// AppendDecode decodes src and appends the result to dst. The
+// extended slice and possible error is returned. In case of error
+// dst is extended with the symbols decoded so far.
+func (dec *Decoder) AppendDecode(dst, src []byte) ([]byte, error) {
+	n := len(dst)
+	dst = grow(dst, DecodedLen(len(src)))
+	m, err := dec.Decode(dst[n:], src)
+	return dst[:n+m], err
+}
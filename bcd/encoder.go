@@ -14,6 +14,9 @@ type Encoder struct {
 
 	// if true the 0x45 translates to '54' and vice versa
 	swap bool
+
+	// if true the filler nibble goes first
+	leading bool
 }
 
 func checkBCD(config *BCD) bool {
@@ -26,6 +29,17 @@ func checkBCD(config *BCD) bool {
 		}
 		nibbles[nib] = true
 	}
+	// check all aliases
+	for alias, c := range config.Aliases {
+		if _, ok := config.Map[alias]; ok {
+			// alias shadows the symbol
+			return false
+		}
+		if _, ok := config.Map[c]; !ok {
+			// alias of unknown symbol
+			return false
+		}
+	}
 	return config.Filler <= 0xf
 }
 
@@ -38,6 +52,9 @@ func newHashEnc(config *BCD) (res [0x100]byte) {
 		}
 		res[i] = c
 	}
+	for alias, c := range config.Aliases {
+		res[alias] = config.Map[c]
+	}
 	return
 }
 
@@ -48,9 +65,10 @@ func NewEncoder(config *BCD) *Encoder {
 		panic("BCD table is incorrect")
 	}
 	return &Encoder{
-		hash:   newHashEnc(config),
-		filler: config.Filler,
-		swap:   config.SwapNibbles}
+		hash:    newHashEnc(config),
+		filler:  config.Filler,
+		swap:    config.SwapNibbles,
+		leading: config.LeadingFiller}
 }
 
 func (enc *Encoder) packNibs(nib1, nib2 byte) byte {
@@ -72,6 +90,9 @@ func (enc *Encoder) pack(w []byte) (n int, b byte, err error) {
 		if nib1, nib2 = enc.hash[w[0]], enc.filler; nib1 > 0xf {
 			err = ErrBadInput
 		}
+		if enc.leading {
+			nib1, nib2 = nib2, nib1
+		}
 	default:
 		n = 2
 		if nib1, nib2 = enc.hash[w[0]], enc.hash[w[1]]; nib1 > 0xf || nib2 > 0xf {
@@ -81,6 +102,20 @@ func (enc *Encoder) pack(w []byte) (n int, b byte, err error) {
 	return n, enc.packNibs(nib1, nib2), err
 }
 
+// symbolError returns the error describing unmapped symbol in w
+// which is found at offset off of the input.
+func (enc *Encoder) symbolError(w []byte, off int) error {
+	i := 0
+	if len(w) > 1 && enc.hash[w[0]] <= 0xf {
+		i = 1
+	}
+	return &Error{
+		Offset: off + i,
+		Byte:   w[i],
+		Reason: UnmappedSymbol,
+		Err:    ErrBadInput}
+}
+
 // EncodedLen returns amount of space needed to store bytes after
 // encoding data of length x.
 func EncodedLen(x int) int {
@@ -88,22 +123,49 @@ func EncodedLen(x int) int {
 }
 
 // Encode get input bytes from src and encodes them into BCD data.
-// Number of encoded bytes and possible error is returned.
+// Number of encoded bytes and possible error is returned. The error
+// is of type *Error if src contains unmapped symbols.
 func (enc *Encoder) Encode(dst, src []byte) (n int, err error) {
 	var b byte
-	var wid int
+	var wid, off int
+
+	if enc.leading && len(src)%2 != 0 && len(dst) > 0 {
+		// filler goes to the first octet
+		if _, dst[0], err = enc.pack(src[:1]); err != nil {
+			return 0, enc.symbolError(src, 0)
+		}
+		n, off = 1, 1
+	}
 
 	for n < len(dst) {
-		wid, b, err = enc.pack(src)
+		wid, b, err = enc.pack(src[off:])
 		switch {
 		case err != nil:
-			return
+			return n, enc.symbolError(src[off:], off)
 		case wid == 0:
 			return
 		}
 		dst[n] = b
 		n++
-		src = src[wid:]
+		off += wid
 	}
 	return
 }
+
+// grow extends dst by x bytes reallocating it if needed.
+func grow(dst []byte, x int) []byte {
+	if n := len(dst) + x; n <= cap(dst) {
+		return dst[:n]
+	}
+	return append(dst, make([]byte, x)...)
+}
+
+// AppendEncode encodes src and appends the result to dst. The
+// extended slice and possible error is returned. In case of error
+// dst is extended with the octets encoded so far.
+func (enc *Encoder) AppendEncode(dst, src []byte) ([]byte, error) {
+	n := len(dst)
+	dst = grow(dst, EncodedLen(len(src)))
+	m, err := enc.Encode(dst[n:], src)
+	return dst[:n+m], err
+}
@@ -0,0 +1,68 @@
+/*
+Package identity implements 3GPP subscriber and equipment identities
+(IMSI, IMEI, IMEISV and MSISDN) as defined in 3GPP TS 23.003 and
+their TBCD encoding as in 3GPP TS 29.002.
+
+The identities implement AppendTo method of field.Serializable. As
+with PLMN of package bcd, AppendTo appends nothing if the identity is
+not valid, so validate it with Parse and New functions beforehand.
+*/
+package identity
+
+import (
+	"fmt"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+// Error values returned by API.
+var (
+	// ErrFormat returned if the identity has invalid length or
+	// contains non-decimal digits.
+	ErrFormat = fmt.Errorf("invalid identity format")
+	// ErrCheckDigit returned if the check digit of IMEI is wrong.
+	ErrCheckDigit = fmt.Errorf("invalid check digit")
+)
+
+var tbcd = bcd.NewCodec(bcd.Telephony)
+
+// isDigits tells if s consists of decimal digits and its length is
+// within [min, max].
+func isDigits(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeDigits decodes TBCD encoded src into the string of decimal
+// digits which length is within [min, max].
+func decodeDigits(src []byte, min, max int) (string, error) {
+	dst := make([]byte, bcd.DecodedLen(len(src)))
+	n, err := tbcd.Decode(dst, src)
+	if err != nil {
+		return "", err
+	}
+
+	if s := string(dst[:n]); isDigits(s, min, max) {
+		return s, nil
+	}
+	return "", ErrFormat
+}
+
+// appendDigits appends TBCD encoded s to dst if s consists of
+// decimal digits and its length is within [min, max]. Otherwise dst
+// is returned unchanged.
+func appendDigits(dst []byte, s string, min, max int) []byte {
+	if !isDigits(s, min, max) {
+		return dst
+	}
+
+	dst, _ = tbcd.AppendEncode(dst, []byte(s))
+	return dst
+}
@@ -0,0 +1,145 @@
+package identity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+func assert(t testing.TB, expected bool, args ...interface{}) {
+	if !expected {
+		t.Helper()
+		t.Fatal(args...)
+	}
+}
+
+func TestIMSI(t *testing.T) {
+	src := []byte{0x52, 0x10, 0x10, 0x32, 0x54, 0x76, 0x98, 0xf0}
+	id, err := DecodeIMSI(src)
+	assert(t, err == nil, err)
+	assert(t, id == "250101234567890", id)
+	assert(t, bytes.Equal(id.AppendTo(nil), src))
+
+	mcc, mnc, msin := id.Split(nil)
+	assert(t, mcc == "250" && mnc == "10" && msin == "1234567890")
+	assert(t, id.MCC() == "250")
+
+	id = "310150123456789"
+	assert(t, id.MNC(nil) == "150", id.MNC(nil))
+	assert(t, id.MSIN(nil) == "123456789")
+
+	table := MNCLengths{"310": 3, "31026": 2}
+	assert(t, IMSI("310260123456789").MNC(table) == "26")
+	assert(t, IMSI("310150123456789").MNC(table) == "150")
+
+	_, err = DecodeIMSI([]byte{0x52, 0xf0})
+	assert(t, err == ErrFormat, err)
+
+	_, err = DecodeIMSI([]byte{0x52, 0x10, 0x10, 0xc2})
+	assert(t, err == ErrFormat, err)
+
+	_, err = DecodeIMSI([]byte{0x52, 0x10, 0xf0, 0x32})
+	assert(t, errors.Is(err, bcd.ErrBadBCD), err)
+
+	_, err = ParseIMSI("25001x")
+	assert(t, err == ErrFormat, err)
+}
+
+func TestIMEI(t *testing.T) {
+	assert(t, LuhnDigit("49015420323751") == '8')
+
+	id, err := NewIMEI("49015420323751")
+	assert(t, err == nil, err)
+	assert(t, id == "490154203237518" && id.Valid())
+	assert(t, id.TAC() == "49015420" && id.SNR() == "323751")
+
+	_, err = ParseIMEI("490154203237519")
+	assert(t, err == ErrCheckDigit, err)
+
+	src := id.AppendTo(nil)
+	assert(t, bytes.Equal(src, []byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0xf8}), src)
+
+	// check digit is spare
+	src[7] = 0xf0
+	id, err = DecodeIMEI(src)
+	assert(t, err == nil, err)
+	assert(t, !id.Valid())
+
+	sv, err := DecodeIMEISV([]byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0x21})
+	assert(t, err == nil, err)
+	assert(t, sv.SVN() == "12" && sv.TAC() == "49015420" && sv.SNR() == "323751")
+	assert(t, sv.IMEI() == "490154203237518")
+}
+
+func TestMSISDN(t *testing.T) {
+	src := []byte{0x91, 0x77, 0x10, 0x32, 0x54, 0x76, 0xf8}
+	id, err := DecodeMSISDN(src)
+	assert(t, err == nil, err)
+	assert(t, id.TON == TONInternational && id.NPI == NPIISDN)
+	assert(t, id.String() == "+77012345678", id)
+	assert(t, bytes.Equal(id.AppendTo(nil), src))
+
+	id, err = ParseMSISDN("87012345678")
+	assert(t, err == nil, err)
+	assert(t, id.TON == TONUnknown && id.Digits == "87012345678")
+
+	_, err = ParseMSISDN("+")
+	assert(t, err == ErrFormat, err)
+}
+
+func TestUnvalidated(t *testing.T) {
+	data := []byte{0xaa}
+
+	imsi := IMSI("12")
+	assert(t, imsi.MCC() == "" && imsi.MNC(nil) == "" && imsi.MSIN(nil) == "")
+	mcc, mnc, msin := IMSI("2500").Split(nil)
+	assert(t, mcc == "" && mnc == "" && msin == "")
+	assert(t, bytes.Equal(imsi.AppendTo(data), data))
+	assert(t, bytes.Equal(IMSI("25001234x").AppendTo(data), data))
+
+	imei := IMEI("4901")
+	assert(t, imei.TAC() == "" && imei.SNR() == "" && !imei.Valid())
+	assert(t, bytes.Equal(imei.AppendTo(data), data))
+
+	sv := IMEISV("490154203237")
+	assert(t, sv.TAC() == "49015420" && sv.SNR() == "" && sv.SVN() == "")
+	assert(t, sv.IMEI() == "")
+	assert(t, bytes.Equal(sv.AppendTo(data), data))
+
+	msisdn := MSISDN{TON: TONInternational, NPI: NPIISDN, Digits: "7x"}
+	assert(t, bytes.Equal(msisdn.AppendTo(data), data))
+}
+
+func TestMarshalText(t *testing.T) {
+	var v struct {
+		IMSI   IMSI
+		IMEI   IMEI
+		MSISDN MSISDN
+	}
+
+	data := `{"IMSI":"250101234567890","IMEI":"490154203237518","MSISDN":"+77012345678"}`
+	assert(t, json.Unmarshal([]byte(data), &v) == nil)
+	assert(t, v.MSISDN.TON == TONInternational)
+
+	out, err := json.Marshal(&v)
+	assert(t, err == nil, err)
+	assert(t, string(out) == data, string(out))
+
+	data = `{"IMEI":"490154203237519"}`
+	assert(t, json.Unmarshal([]byte(data), &v) == ErrCheckDigit)
+}
+
+func ExampleIMSI_Split() {
+	id, err := DecodeIMSI([]byte{0x13, 0x10, 0x05, 0x21, 0x43, 0x65, 0x87, 0xf9})
+	if err != nil {
+		return
+	}
+
+	mcc, mnc, msin := id.Split(nil)
+	fmt.Println(mcc, mnc, msin)
+	// Output: 310 150 123456789
+}
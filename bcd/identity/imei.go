@@ -0,0 +1,184 @@
+package identity
+
+// IMEI is International Mobile station Equipment Identity. It
+// consists of Type Allocation Code (TAC, 8 digits), Serial Number
+// (SNR, 6 digits) and Check Digit (CD, 1 digit).
+type IMEI string
+
+// IMEISV is International Mobile station Equipment Identity and
+// Software Version number. It consists of TAC, SNR and Software
+// Version Number (SVN, 2 digits).
+type IMEISV string
+
+// Lengths of equipment identities.
+const (
+	IMEILen   = 15
+	IMEISVLen = 16
+)
+
+// LuhnDigit computes the check digit of the decimal string s using
+// the Luhn algorithm.
+func LuhnDigit(s string) byte {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		d := int(s[len(s)-1-i] - '0')
+		if i%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return '0' + byte((10-sum%10)%10)
+}
+
+// NewIMEI returns IMEI made of 14 digits of TAC and SNR followed by
+// the computed check digit.
+func NewIMEI(tacSNR string) (IMEI, error) {
+	if !isDigits(tacSNR, IMEILen-1, IMEILen-1) {
+		return "", ErrFormat
+	}
+	return IMEI(tacSNR + string(LuhnDigit(tacSNR))), nil
+}
+
+// ParseIMEI validates s including its check digit and returns it as
+// IMEI.
+func ParseIMEI(s string) (IMEI, error) {
+	if !isDigits(s, IMEILen, IMEILen) {
+		return "", ErrFormat
+	}
+	if id := IMEI(s); !id.Valid() {
+		return "", ErrCheckDigit
+	}
+	return IMEI(s), nil
+}
+
+// DecodeIMEI decodes TBCD encoded IMEI from src. The check digit is
+// not verified since it may be transmitted as zero. Use Valid to
+// verify it.
+func DecodeIMEI(src []byte) (IMEI, error) {
+	s, err := decodeDigits(src, IMEILen, IMEILen)
+	return IMEI(s), err
+}
+
+// Valid tells if the check digit of IMEI is correct.
+func (id IMEI) Valid() bool {
+	return len(id) == IMEILen && LuhnDigit(string(id[:IMEILen-1])) == id[IMEILen-1]
+}
+
+// TAC returns Type Allocation Code of IMEI or empty string if IMEI
+// is too short.
+func (id IMEI) TAC() string {
+	return tac(string(id))
+}
+
+// SNR returns Serial Number of IMEI or empty string if IMEI is too
+// short.
+func (id IMEI) SNR() string {
+	return snr(string(id))
+}
+
+// AppendTo appends TBCD encoded IMEI to data and returns the
+// resulting slice. Invalid IMEI is not appended.
+func (id IMEI) AppendTo(data []byte) []byte {
+	return appendDigits(data, string(id), IMEILen, IMEILen)
+}
+
+// String implements fmt.Stringer interface.
+func (id IMEI) String() string {
+	return string(id)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (id IMEI) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (id *IMEI) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseIMEI(string(text))
+	return
+}
+
+// ParseIMEISV validates s and returns it as IMEISV.
+func ParseIMEISV(s string) (IMEISV, error) {
+	if !isDigits(s, IMEISVLen, IMEISVLen) {
+		return "", ErrFormat
+	}
+	return IMEISV(s), nil
+}
+
+// DecodeIMEISV decodes TBCD encoded IMEISV from src.
+func DecodeIMEISV(src []byte) (IMEISV, error) {
+	s, err := decodeDigits(src, IMEISVLen, IMEISVLen)
+	return IMEISV(s), err
+}
+
+// TAC returns Type Allocation Code of IMEISV or empty string if
+// IMEISV is too short.
+func (id IMEISV) TAC() string {
+	return tac(string(id))
+}
+
+// SNR returns Serial Number of IMEISV or empty string if IMEISV is
+// too short.
+func (id IMEISV) SNR() string {
+	return snr(string(id))
+}
+
+// SVN returns Software Version Number of IMEISV or empty string if
+// IMEISV is too short.
+func (id IMEISV) SVN() string {
+	if len(id) < IMEISVLen {
+		return ""
+	}
+	return string(id[14:IMEISVLen])
+}
+
+// IMEI returns IMEI of the equipment with the computed check digit.
+// Empty IMEI is returned if IMEISV is invalid.
+func (id IMEISV) IMEI() IMEI {
+	if len(id) < 14 {
+		return ""
+	}
+	imei, _ := NewIMEI(string(id[:14]))
+	return imei
+}
+
+// AppendTo appends TBCD encoded IMEISV to data and returns the
+// resulting slice. Invalid IMEISV is not appended.
+func (id IMEISV) AppendTo(data []byte) []byte {
+	return appendDigits(data, string(id), IMEISVLen, IMEISVLen)
+}
+
+// tac returns TAC of IMEI or IMEISV s.
+func tac(s string) string {
+	if len(s) < 8 {
+		return ""
+	}
+	return s[:8]
+}
+
+// snr returns SNR of IMEI or IMEISV s.
+func snr(s string) string {
+	if len(s) < 14 {
+		return ""
+	}
+	return s[8:14]
+}
+
+// String implements fmt.Stringer interface.
+func (id IMEISV) String() string {
+	return string(id)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (id IMEISV) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (id *IMEISV) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseIMEISV(string(text))
+	return
+}
@@ -0,0 +1,114 @@
+package identity
+
+// IMSI is International Mobile Subscriber Identity. It consists of
+// Mobile Country Code (MCC, 3 digits), Mobile Network Code (MNC, 2
+// or 3 digits) and Mobile Subscriber Identification Number (MSIN).
+// The total length of IMSI doesn't exceed 15 digits.
+type IMSI string
+
+// Length limits of IMSI.
+const (
+	MinIMSILen = 6
+	MaxIMSILen = 15
+)
+
+// MNCLengths maps MCC to the length of MNC used in the country. For
+// countries which use both 2- and 3-digit MNC the key may also be
+// MCC followed by the first 2 digits of MNC.
+type MNCLengths map[string]int
+
+// DefaultMNCLengths lists the countries which use 3-digit MNC. All
+// the other countries are assumed to use 2-digit MNC.
+var DefaultMNCLengths = MNCLengths{
+	"302": 3, "310": 3, "311": 3, "312": 3, "313": 3, "314": 3,
+	"315": 3, "316": 3, "334": 3, "338": 3, "342": 3, "344": 3,
+	"346": 3, "348": 3, "354": 3, "356": 3, "358": 3, "360": 3,
+	"365": 3, "376": 3, "405": 3, "708": 3, "722": 3, "732": 3,
+}
+
+// Len returns the length of MNC in the IMSI or PLMN identity s.
+func (t MNCLengths) Len(s string) int {
+	if len(s) >= 5 {
+		if n, ok := t[s[:5]]; ok {
+			return n
+		}
+	}
+	if len(s) >= 3 {
+		if n, ok := t[s[:3]]; ok {
+			return n
+		}
+	}
+	return 2
+}
+
+// ParseIMSI validates s and returns it as IMSI.
+func ParseIMSI(s string) (IMSI, error) {
+	if !isDigits(s, MinIMSILen, MaxIMSILen) {
+		return "", ErrFormat
+	}
+	return IMSI(s), nil
+}
+
+// DecodeIMSI decodes TBCD encoded IMSI from src.
+func DecodeIMSI(src []byte) (IMSI, error) {
+	s, err := decodeDigits(src, MinIMSILen, MaxIMSILen)
+	return IMSI(s), err
+}
+
+// AppendTo appends TBCD encoded IMSI to data and returns the
+// resulting slice. Invalid IMSI is not appended.
+func (id IMSI) AppendTo(data []byte) []byte {
+	return appendDigits(data, string(id), MinIMSILen, MaxIMSILen)
+}
+
+// MCC returns Mobile Country Code of IMSI or empty string if IMSI is
+// too short.
+func (id IMSI) MCC() string {
+	if len(id) < 3 {
+		return ""
+	}
+	return string(id[:3])
+}
+
+// Split splits IMSI into MCC, MNC and MSIN. The length of MNC is
+// determined by t. If t is nil DefaultMNCLengths is used. Empty
+// strings are returned if IMSI is too short.
+func (id IMSI) Split(t MNCLengths) (mcc, mnc, msin string) {
+	if t == nil {
+		t = DefaultMNCLengths
+	}
+	n := 3 + t.Len(string(id))
+	if len(id) < n {
+		return "", "", ""
+	}
+	return string(id[:3]), string(id[3:n]), string(id[n:])
+}
+
+// MNC returns Mobile Network Code of IMSI. See Split.
+func (id IMSI) MNC(t MNCLengths) string {
+	_, mnc, _ := id.Split(t)
+	return mnc
+}
+
+// MSIN returns Mobile Subscriber Identification Number of IMSI. See
+// Split.
+func (id IMSI) MSIN(t MNCLengths) string {
+	_, _, msin := id.Split(t)
+	return msin
+}
+
+// String implements fmt.Stringer interface.
+func (id IMSI) String() string {
+	return string(id)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (id IMSI) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (id *IMSI) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseIMSI(string(text))
+	return
+}
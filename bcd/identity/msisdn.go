@@ -0,0 +1,108 @@
+package identity
+
+import (
+	"strings"
+)
+
+// Type of number (nature of address) values of MSISDN.
+const (
+	TONUnknown         byte = 0x0
+	TONInternational   byte = 0x1
+	TONNational        byte = 0x2
+	TONNetworkSpecific byte = 0x3
+	TONSubscriber      byte = 0x4
+	TONAbbreviated     byte = 0x6
+)
+
+// Numbering plan indicator values of MSISDN.
+const (
+	NPIUnknown    byte = 0x0
+	NPIISDN       byte = 0x1
+	NPIData       byte = 0x3
+	NPITelex      byte = 0x4
+	NPILandMobile byte = 0x6
+	NPINational   byte = 0x8
+	NPIPrivate    byte = 0x9
+)
+
+// MaxMSISDNLen is the maximum number of digits in MSISDN.
+const MaxMSISDNLen = 15
+
+// MSISDN is Mobile Station International ISDN Number. It is encoded
+// as AddressString of 3GPP TS 29.002, i.e. the octet of type of
+// number and numbering plan indicator followed by TBCD encoded
+// digits.
+type MSISDN struct {
+	// Type of number, 3 bits.
+	TON byte
+	// Numbering plan indicator, 4 bits.
+	NPI byte
+	// Decimal digits of the number.
+	Digits string
+}
+
+// ParseMSISDN parses MSISDN in text form. If s starts with '+' the
+// number is international, otherwise its type is unknown. The
+// numbering plan is ISDN.
+func ParseMSISDN(s string) (MSISDN, error) {
+	id := MSISDN{TON: TONUnknown, NPI: NPIISDN}
+	if strings.HasPrefix(s, "+") {
+		id.TON, s = TONInternational, s[1:]
+	}
+
+	if !isDigits(s, 1, MaxMSISDNLen) {
+		return MSISDN{}, ErrFormat
+	}
+
+	id.Digits = s
+	return id, nil
+}
+
+// DecodeMSISDN decodes MSISDN from src.
+func DecodeMSISDN(src []byte) (MSISDN, error) {
+	if len(src) < 2 {
+		return MSISDN{}, ErrFormat
+	}
+
+	s, err := decodeDigits(src[1:], 1, MaxMSISDNLen)
+	if err != nil {
+		return MSISDN{}, err
+	}
+
+	return MSISDN{
+		TON:    (src[0] >> 4) & 0x7,
+		NPI:    src[0] & 0xf,
+		Digits: s}, nil
+}
+
+// AppendTo appends encoded MSISDN to data and returns the resulting
+// slice. MSISDN with invalid digits is not appended.
+func (id MSISDN) AppendTo(data []byte) []byte {
+	if !isDigits(id.Digits, 1, MaxMSISDNLen) {
+		return data
+	}
+
+	data = append(data, 0x80|(id.TON&0x7)<<4|id.NPI&0xf)
+	return appendDigits(data, id.Digits, 1, MaxMSISDNLen)
+}
+
+// String implements fmt.Stringer interface. International number is
+// prefixed with '+'.
+func (id MSISDN) String() string {
+	if id.TON == TONInternational {
+		return "+" + id.Digits
+	}
+	return id.Digits
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (id MSISDN) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. See
+// ParseMSISDN.
+func (id *MSISDN) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseMSISDN(string(text))
+	return
+}
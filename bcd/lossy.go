@@ -0,0 +1,72 @@
+package bcd
+
+// Default symbols used by DecodeLossy.
+const (
+	DefaultPlaceholder byte = '?'
+	DefaultFillerMark  byte = '_'
+)
+
+// decodeLossy decodes src into dst nibble by nibble. For every
+// invalid nibble the substitute symbol is written and fn is called,
+// if specified.
+func (dec *Decoder) decodeLossy(dst, src []byte, fn func(*Error)) (n int) {
+	placeholder, mark := dec.Placeholder, dec.FillerMark
+	if placeholder == 0 {
+		placeholder = DefaultPlaceholder
+	}
+	if mark == 0 {
+		mark = DefaultFillerMark
+	}
+
+	// filler is legitimate in this nibble of an octet
+	legal := 1
+	if dec.leading {
+		legal = 0
+	}
+
+	var nibs [2]byte
+	last := dec.fillerAt(len(src))
+	for i, b := range src {
+		nibs[0], nibs[1] = dec.unpackNibs(b)
+		for j, nib := range nibs {
+			if n == len(dst) {
+				return
+			}
+
+			c, reason := dec.hashNib[nib], Reason(0)
+			switch {
+			case c != 0xff:
+			case nib != dec.filler:
+				c, reason = placeholder, UnmappedNibble
+			case j == legal && (i == last || dec.IgnoreFiller):
+				continue
+			default:
+				c, reason = mark, UnexpectedFiller
+			}
+
+			if reason != 0 && fn != nil {
+				fn(&Error{
+					Offset: i,
+					Byte:   b,
+					High:   (j == 0) != dec.swap,
+					Reason: reason,
+					Err:    ErrBadBCD})
+			}
+
+			dst[n] = c
+			n++
+		}
+	}
+	return
+}
+
+// DecodeLossy decodes BCD encoded bytes from src into dst and never
+// fails. Unmapped nibbles are rendered as Placeholder and unexpected
+// filler nibbles are rendered as FillerMark. It is intended for
+// logging and troubleshooting of malformed data.
+//
+// Number of decoded bytes and number of invalid nibbles is returned.
+func (dec *Decoder) DecodeLossy(dst, src []byte) (n, invalid int) {
+	n = dec.decodeLossy(dst, src, func(*Error) { invalid++ })
+	return
+}
@@ -0,0 +1,52 @@
+package bcd
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDecodeLossy(t *testing.T) {
+	assert := newAssert(t, false)
+	dec := NewDecoder(Telephony)
+	dst := make([]byte, 20)
+
+	n, invalid := dec.DecodeLossy(dst, []byte{0x21, 0x43, 0xf5})
+	assert(string(dst[:n]) == "12345")
+	assert(invalid == 0)
+
+	n, invalid = dec.DecodeLossy(dst, []byte{0x21, 0xf3, 0xff, 0x65})
+	assert(string(dst[:n]) == "123___56")
+	assert(invalid == 3)
+
+	dec.IgnoreFiller = true
+	dec.FillerMark = 'F'
+	n, invalid = dec.DecodeLossy(dst, []byte{0x21, 0xf3, 0xff, 0x65})
+	assert(string(dst[:n]) == "123F56")
+	assert(invalid == 1)
+
+	dec = NewDecoder(Standard)
+	n, invalid = dec.DecodeLossy(dst, []byte{0x12, 0xa4, 0xbf})
+	assert(string(dst[:n]) == "12?4?")
+	assert(invalid == 2)
+
+	dec = NewDecoder(leading)
+	dec.Placeholder = '*'
+	n, invalid = dec.DecodeLossy(dst, []byte{0xf1, 0x2c, 0x3f})
+	assert(string(dst[:n]) == "12*3_")
+	assert(invalid == 2)
+
+	n, invalid = dec.DecodeLossy(dst[:3], []byte{0xf1, 0x23, 0x45})
+	assert(string(dst[:n]) == "123")
+	assert(invalid == 0)
+}
+
+func ExampleDecoder_DecodeLossy() {
+	dec := NewDecoder(Telephony)
+
+	src := []byte{0x21, 0x43, 0xff, 0x87}
+	dst := make([]byte, DecodedLen(len(src)))
+	n, invalid := dec.DecodeLossy(dst, src)
+
+	fmt.Println(string(dst[:n]), invalid)
+	// Output: 1234__78 2
+}
@@ -0,0 +1,145 @@
+package bcd
+
+import (
+	"io"
+	"math"
+	"math/big"
+)
+
+// encodeDigits encodes decimal digits s left-padded with zeros to
+// the specified number of digits. If digits is zero the length of s
+// is used.
+func (enc *Encoder) encodeDigits(dst, s []byte, digits int) (n int, err error) {
+	if digits == 0 {
+		if digits = len(s); digits == 0 {
+			digits = 1
+		}
+	}
+
+	if len(s) > digits {
+		return 0, ErrOverflow
+	}
+
+	if len(dst) < EncodedLen(digits) {
+		return 0, io.ErrShortBuffer
+	}
+
+	var nib1 byte
+	pad := digits - len(s)
+	i := 0
+	if enc.leading && digits%2 != 0 {
+		// treat filler as the first digit
+		nib1, i, pad = enc.filler, 1, pad+1
+		digits++
+	}
+
+	for ; i < digits; i++ {
+		c := byte('0')
+		if i >= pad {
+			c = s[i-pad]
+		}
+
+		nib := enc.hash[c]
+		if nib > 0xf || !isDigit(c) {
+			return 0, ErrBadInput
+		}
+
+		if i%2 == 0 {
+			nib1 = nib
+			continue
+		}
+
+		dst[n] = enc.packNibs(nib1, nib)
+		n++
+	}
+
+	if digits%2 != 0 {
+		dst[n] = enc.packNibs(nib1, enc.filler)
+		n++
+	}
+	return n, nil
+}
+
+// EncodeUint64 encodes decimal representation of x left-padded with
+// zeros to the specified number of digits. If digits is zero no
+// padding is applied. ErrOverflow is returned if x has more digits
+// than specified. Number of encoded bytes and possible error is
+// returned.
+func (enc *Encoder) EncodeUint64(dst []byte, x uint64, digits int) (int, error) {
+	var buf [20]byte
+	i := len(buf)
+	for ; x > 0; x /= 10 {
+		i--
+		buf[i] = '0' + byte(x%10)
+	}
+
+	return enc.encodeDigits(dst, buf[i:], digits)
+}
+
+// EncodeBig encodes decimal representation of non-negative x
+// left-padded with zeros to the specified number of digits. See
+// EncodeUint64 for details.
+func (enc *Encoder) EncodeBig(dst []byte, x *big.Int, digits int) (int, error) {
+	if x.Sign() < 0 {
+		return 0, ErrBadInput
+	}
+
+	return enc.encodeDigits(dst, trimZeros(x.Append(nil, 10)), digits)
+}
+
+// DecodeUint64 decodes BCD encoded decimal number from src.
+// ErrOverflow is returned if the number does not fit into uint64.
+func (dec *Decoder) DecodeUint64(src []byte) (x uint64, err error) {
+	if len(src) == 0 {
+		return 0, ErrBadBCD
+	}
+
+	var w word
+	last := dec.fillerAt(len(src))
+	for i, b := range src {
+		wid, end, err := dec.unpack(w[:], b)
+		switch {
+		case err != nil: // invalid input
+			return 0, dec.octetError(b, i)
+		case end && i != last && !dec.IgnoreFiller:
+			return 0, dec.octetError(b, i)
+		}
+
+		for _, c := range w[:wid] {
+			if !isDigit(c) {
+				return 0, ErrBadBCD
+			}
+
+			d := uint64(c - '0')
+			if x > (math.MaxUint64-d)/10 {
+				return 0, ErrOverflow
+			}
+			x = x*10 + d
+		}
+	}
+
+	return x, nil
+}
+
+// DecodeBig decodes BCD encoded decimal number from src and stores
+// it in x.
+func (dec *Decoder) DecodeBig(src []byte, x *big.Int) error {
+	if len(src) == 0 {
+		return ErrBadBCD
+	}
+
+	dst := make([]byte, DecodedLen(len(src)))
+	n, err := dec.Decode(dst, src)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range dst[:n] {
+		if !isDigit(c) {
+			return ErrBadBCD
+		}
+	}
+
+	x.SetString(string(dst[:n]), 10)
+	return nil
+}
@@ -0,0 +1,114 @@
+package bcd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEncodeUint64(t *testing.T) {
+	assert := newAssert(t, false)
+	enc := NewEncoder(Telephony)
+	dst := make([]byte, 10)
+
+	n, err := enc.EncodeUint64(dst, 12345, 0)
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x21, 0x43, 0xf5}))
+
+	n, err = enc.EncodeUint64(dst, 12345, 8)
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x00, 0x10, 0x32, 0x54}))
+
+	n, err = enc.EncodeUint64(dst, 0, 3)
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x00, 0xf0}))
+
+	_, err = enc.EncodeUint64(dst, 12345, 4)
+	assert(err == ErrOverflow)
+
+	_, err = enc.EncodeUint64(dst[:2], 12345, 5)
+	assert(err != nil)
+}
+
+func TestDecodeUint64(t *testing.T) {
+	assert := newAssert(t, false)
+	dec := NewDecoder(Telephony)
+
+	x, err := dec.DecodeUint64([]byte{0x00, 0x21, 0x43, 0xf5})
+	assert(err == nil)
+	assert(x == 12345)
+
+	_, err = dec.DecodeUint64([]byte{0xdc, 0xfe})
+	assert(errors.Is(err, ErrBadBCD))
+
+	_, err = dec.DecodeUint64([]byte{0x21, 0xf3, 0x54})
+	assert(errors.Is(err, ErrBadBCD))
+
+	enc := NewEncoder(Telephony)
+	dst := make([]byte, 11)
+	n, err := enc.EncodeUint64(dst, math.MaxUint64, 21)
+	assert(err == nil)
+	x, err = dec.DecodeUint64(dst[:n])
+	assert(err == nil)
+	assert(x == math.MaxUint64)
+
+	dst[0] = 0x20
+	_, err = dec.DecodeUint64(dst[:n])
+	assert(err == ErrOverflow)
+}
+
+func TestEncodeBig(t *testing.T) {
+	assert := newAssert(t, true)
+	codec := NewCodec(Aiken)
+	dst := make([]byte, 20)
+
+	x, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	n, err := codec.EncodeBig(dst, x, 31)
+	assert(err == nil)
+	assert(n == 16)
+
+	y := new(big.Int)
+	assert(codec.DecodeBig(dst[:n], y) == nil)
+	assert(x.Cmp(y) == 0)
+
+	_, err = codec.EncodeBig(dst, x, 29)
+	assert(err == ErrOverflow)
+
+	_, err = codec.EncodeBig(dst, x.Neg(x), 31)
+	assert(errors.Is(err, ErrBadInput))
+}
+
+func ExampleEncoder_EncodeUint64() {
+	enc := NewEncoder(Standard)
+
+	dst := make([]byte, EncodedLen(6))
+	n, err := enc.EncodeUint64(dst, 1234, 6)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", dst[:n])
+	// Output: 00 12 34
+}
+
+func BenchmarkEncodeUint64(b *testing.B) {
+	dst := make([]byte, 10)
+	enc := NewEncoder(enc)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		enc.EncodeUint64(dst, 123456789, 12)
+	}
+}
+
+func BenchmarkDecodeUint64(b *testing.B) {
+	dec := NewDecoder(enc)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		dec.DecodeUint64([]byte{0x21, 0x43, 0x65, 0x87})
+	}
+}
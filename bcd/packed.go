@@ -0,0 +1,376 @@
+package bcd
+
+import (
+	"io"
+	"math"
+	"math/big"
+)
+
+// Sign nibbles of packed decimal encoding.
+const (
+	// SignPositive is the preferred sign of a positive value.
+	SignPositive byte = 0xc
+	// SignNegative is the preferred sign of a negative value.
+	SignNegative byte = 0xd
+	// SignUnsigned is the sign of an unsigned value.
+	SignUnsigned byte = 0xf
+)
+
+// Packed is used to encode and decode packed decimal (COMP-3)
+// numbers. Packed decimal field has fixed number of digits followed
+// by the sign nibble. If the number of digits is even the field
+// starts with a zero nibble so that the field occupies whole number
+// of octets.
+//
+// Scale is the number of implied decimal places in the field. The
+// field of 7 digits and scale 2 holds values up to 99999.99. Integer
+// conversions operate on unscaled values, i.e. 12345 in such field
+// means 123.45.
+//
+// On decoding 0xA, 0xC, 0xE and 0xF sign nibbles are treated as
+// positive and 0xB and 0xD as negative.
+//
+// Packed may be copied with no side effects.
+type Packed struct {
+	// If true non-negative values are encoded with SignUnsigned
+	// instead of SignPositive, and negative values are rejected.
+	Unsigned bool
+
+	enc    Encoder
+	dec    Decoder
+	digits int
+	scale  int
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// nibbleShift returns the bit shift of i-th nibble within its octet
+// if the nibbles are counted in the order of encoded symbols.
+func nibbleShift(i int, swap bool) uint {
+	if (i%2 == 0) != swap {
+		return 4
+	}
+	return 0
+}
+
+func getNibble(src []byte, i int, swap bool) byte {
+	return (src[i/2] >> nibbleShift(i, swap)) & 0xf
+}
+
+func setNibble(dst []byte, i int, nib byte, swap bool) {
+	shift := nibbleShift(i, swap)
+	dst[i/2] = dst[i/2]&^(0xf<<shift) | (nib&0xf)<<shift
+}
+
+// NewPacked creates new Packed from BCD configuration for the field
+// of specified number of digits and scale. The configuration should
+// map all decimal digits. Filler of the configuration is not used.
+// NewPacked will panic if any of the arguments is invalid.
+func NewPacked(config *BCD, digits, scale int) *Packed {
+	if digits < 1 || scale < 0 || scale > digits {
+		panic("invalid packed decimal field size")
+	}
+
+	for c := byte('0'); c <= '9'; c++ {
+		if _, ok := config.Map[c]; !ok {
+			panic("BCD table does not map decimal digits")
+		}
+	}
+
+	return &Packed{
+		enc:    *NewEncoder(config),
+		dec:    *NewDecoder(config),
+		digits: digits,
+		scale:  scale}
+}
+
+// Digits returns the number of digits in the field.
+func (p *Packed) Digits() int {
+	return p.digits
+}
+
+// Scale returns the number of implied decimal places in the field.
+func (p *Packed) Scale() int {
+	return p.scale
+}
+
+// EncodedLen returns the number of octets in the field.
+func (p *Packed) EncodedLen() int {
+	return p.digits/2 + 1
+}
+
+// DecodedLen returns the maximum length of textual representation
+// of the field value.
+func (p *Packed) DecodedLen() int {
+	n := 1 + p.digits // sign
+	if p.scale == p.digits {
+		n++ // leading zero
+	}
+	if p.scale > 0 {
+		n++ // decimal point
+	}
+	return n
+}
+
+// pack encodes unscaled value represented by digits a and b followed
+// by z zeros. The value is right-aligned in the field.
+func (p *Packed) pack(dst, a, b []byte, z int, neg bool) (int, error) {
+	n := p.EncodedLen()
+	if len(dst) < n {
+		return 0, io.ErrShortBuffer
+	}
+
+	if len(a)+len(b)+z > p.digits {
+		return 0, ErrOverflow
+	}
+
+	sign := SignPositive
+	if neg {
+		if p.Unsigned {
+			return 0, ErrBadInput
+		}
+		sign = SignNegative
+	} else if p.Unsigned {
+		sign = SignUnsigned
+	}
+
+	enc := &p.enc
+	zero := enc.hash['0']
+	i := 0
+	for ; i < 2*n-1-len(a)-len(b)-z; i++ {
+		setNibble(dst, i, zero, enc.swap)
+	}
+
+	for _, s := range [][]byte{a, b} {
+		for _, c := range s {
+			if !isDigit(c) {
+				return 0, ErrBadInput
+			}
+			setNibble(dst, i, enc.hash[c], enc.swap)
+			i++
+		}
+	}
+
+	for ; z > 0; z-- {
+		setNibble(dst, i, zero, enc.swap)
+		i++
+	}
+
+	setNibble(dst, i, sign, enc.swap)
+	return n, nil
+}
+
+func trimZeros(s []byte) []byte {
+	for len(s) > 0 && s[0] == '0' {
+		s = s[1:]
+	}
+	return s
+}
+
+func isZero(s []byte) bool {
+	return len(trimZeros(s)) == 0
+}
+
+// Encode encodes textual decimal number from src into packed decimal
+// field. The number may be prefixed with '+' or '-' sign and may
+// contain the decimal point followed by no more than Scale digits.
+// Number of encoded bytes and possible error is returned.
+func (p *Packed) Encode(dst, src []byte) (n int, err error) {
+	var neg bool
+	if len(src) > 0 && (src[0] == '-' || src[0] == '+') {
+		neg, src = src[0] == '-', src[1:]
+	}
+
+	a, b := src, []byte(nil)
+	for i, c := range src {
+		if c == '.' {
+			a, b = src[:i], src[i+1:]
+			break
+		}
+	}
+
+	if len(a)+len(b) == 0 || len(b) > p.scale {
+		return 0, ErrBadInput
+	}
+
+	if isZero(a) && isZero(b) {
+		neg = false
+	}
+
+	return p.pack(dst, trimZeros(a), b, p.scale-len(b), neg)
+}
+
+// sign returns true if the field in src is negative.
+func (p *Packed) sign(src []byte) (neg bool, err error) {
+	n := p.EncodedLen()
+	if len(src) < n {
+		return false, ErrBadBCD
+	}
+
+	switch getNibble(src, 2*n-1, p.dec.swap) {
+	case 0xa, 0xc, 0xe, 0xf:
+		neg = false
+	case 0xb, 0xd:
+		neg = true
+	default:
+		return false, ErrBadBCD
+	}
+
+	if neg && p.Unsigned {
+		return false, ErrBadBCD
+	}
+
+	// padding nibble should be zero
+	if p.digits%2 == 0 && p.digit(src, -1) != '0' {
+		return false, ErrBadBCD
+	}
+
+	return neg, nil
+}
+
+// digit returns i-th decimal digit of the field or 0 if the digit is
+// invalid.
+func (p *Packed) digit(src []byte, i int) byte {
+	i += 1 - p.digits%2 // padding
+	c := p.dec.hashNib[getNibble(src, i, p.dec.swap)]
+	if !isDigit(c) {
+		return 0
+	}
+	return c
+}
+
+// Decode decodes packed decimal field from src into its textual
+// representation in dst. Leading zeros of the value are omitted and
+// the decimal point is inserted if Scale is not zero. Number of
+// decoded bytes and possible error is returned.
+func (p *Packed) Decode(dst, src []byte) (n int, err error) {
+	if len(dst) < p.DecodedLen() {
+		return 0, io.ErrShortBuffer
+	}
+
+	neg, err := p.sign(src)
+	if err != nil {
+		return 0, err
+	}
+
+	if neg {
+		dst[n] = '-'
+		n++
+	}
+
+	lead := true
+	for i := 0; i < p.digits; i++ {
+		c := p.digit(src, i)
+		if c == 0 {
+			return 0, ErrBadBCD
+		}
+
+		if i == p.digits-p.scale {
+			if lead {
+				dst[n] = '0'
+				n++
+			}
+			dst[n] = '.'
+			n++
+			lead = false
+		}
+
+		if lead = lead && c == '0'; !lead {
+			dst[n] = c
+			n++
+		}
+	}
+
+	if lead {
+		dst[n] = '0'
+		n++
+	}
+
+	return n, nil
+}
+
+// EncodeInt64 encodes unscaled integer value x into packed decimal
+// field. Number of encoded bytes and possible error is returned.
+func (p *Packed) EncodeInt64(dst []byte, x int64) (int, error) {
+	var buf [20]byte
+	u := uint64(x)
+	if x < 0 {
+		u = -u
+	}
+
+	i := len(buf)
+	for ; u > 0; u /= 10 {
+		i--
+		buf[i] = '0' + byte(u%10)
+	}
+
+	return p.pack(dst, buf[i:], nil, 0, x < 0)
+}
+
+// DecodeInt64 decodes packed decimal field from src and returns its
+// unscaled integer value.
+func (p *Packed) DecodeInt64(src []byte) (int64, error) {
+	neg, err := p.sign(src)
+	if err != nil {
+		return 0, err
+	}
+
+	var u uint64
+	for i := 0; i < p.digits; i++ {
+		c := p.digit(src, i)
+		if c == 0 {
+			return 0, ErrBadBCD
+		}
+		if u > (math.MaxUint64-9)/10 {
+			return 0, ErrOverflow
+		}
+		u = u*10 + uint64(c-'0')
+	}
+
+	switch {
+	case !neg && u > math.MaxInt64:
+		return 0, ErrOverflow
+	case neg && u > -math.MinInt64:
+		return 0, ErrOverflow
+	case neg:
+		return -int64(u), nil
+	}
+	return int64(u), nil
+}
+
+// EncodeBig encodes unscaled integer value x into packed decimal
+// field. Number of encoded bytes and possible error is returned.
+func (p *Packed) EncodeBig(dst []byte, x *big.Int) (int, error) {
+	s := x.Append(nil, 10)
+	if x.Sign() < 0 {
+		s = s[1:]
+	}
+	return p.pack(dst, trimZeros(s), nil, 0, x.Sign() < 0)
+}
+
+// DecodeBig decodes packed decimal field from src and stores its
+// unscaled integer value in x.
+func (p *Packed) DecodeBig(src []byte, x *big.Int) error {
+	neg, err := p.sign(src)
+	if err != nil {
+		return err
+	}
+
+	s := make([]byte, 0, p.digits+1)
+	if neg {
+		s = append(s, '-')
+	}
+
+	for i := 0; i < p.digits; i++ {
+		c := p.digit(src, i)
+		if c == 0 {
+			return ErrBadBCD
+		}
+		s = append(s, c)
+	}
+
+	x.SetString(string(s), 10)
+	return nil
+}
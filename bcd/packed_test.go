@@ -0,0 +1,149 @@
+package bcd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestPackedEncode(t *testing.T) {
+	assert := newAssert(t, false)
+	p := NewPacked(Standard, 5, 2)
+	dst := make([]byte, p.EncodedLen())
+	assert(len(dst) == 3)
+
+	n, err := p.Encode(dst, []byte("-123.45"))
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x12, 0x34, 0x5d}))
+
+	n, err = p.Encode(dst, []byte("+1.5"))
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x00, 0x15, 0x0c}))
+
+	n, err = p.Encode(dst, []byte("0007"))
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x00, 0x70, 0x0c}))
+
+	_, err = p.Encode(dst, []byte("1234"))
+	assert(err == ErrOverflow)
+
+	_, err = p.Encode(dst, []byte("1.234"))
+	assert(err == ErrBadInput)
+
+	_, err = p.Encode(dst, []byte("1a"))
+	assert(err == ErrBadInput)
+
+	// even number of digits is padded with leading zero
+	p = NewPacked(Standard, 4, 0)
+	n, err = p.EncodeInt64(dst, -1234)
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x01, 0x23, 0x4d}))
+
+	p.Unsigned = true
+	n, err = p.EncodeInt64(dst, 1234)
+	assert(err == nil)
+	assert(bytes.Equal(dst[:n], []byte{0x01, 0x23, 0x4f}))
+
+	_, err = p.EncodeInt64(dst, -1)
+	assert(err == ErrBadInput)
+}
+
+func TestPackedDecode(t *testing.T) {
+	assert := newAssert(t, false)
+	p := NewPacked(Standard, 5, 2)
+	dst := make([]byte, p.DecodedLen())
+
+	n, err := p.Decode(dst, []byte{0x12, 0x34, 0x5d})
+	assert(err == nil)
+	assert(string(dst[:n]) == "-123.45")
+
+	n, err = p.Decode(dst, []byte{0x00, 0x01, 0x5f})
+	assert(err == nil)
+	assert(string(dst[:n]) == "0.15")
+
+	_, err = p.Decode(dst, []byte{0x00, 0x01, 0x59})
+	assert(err == ErrBadBCD)
+
+	_, err = p.Decode(dst, []byte{0x00, 0xa1, 0x5c})
+	assert(err == ErrBadBCD)
+
+	p = NewPacked(Standard, 3, 3)
+	dst = make([]byte, p.DecodedLen())
+	n, err = p.Decode(dst, []byte{0x12, 0x3d})
+	assert(err == nil)
+	assert(string(dst[:n]) == "-0.123")
+
+	p = NewPacked(Standard, 4, 0)
+	_, err = p.DecodeInt64([]byte{0x11, 0x23, 0x4c})
+	assert(err == ErrBadBCD)
+}
+
+func TestPackedInt64(t *testing.T) {
+	assert := newAssert(t, true)
+	p := NewPacked(Telephony, 19, 0)
+	dst := make([]byte, p.EncodedLen())
+
+	for _, x := range []int64{0, 1, -1, 1234567, math.MaxInt64, math.MinInt64} {
+		n, err := p.EncodeInt64(dst, x)
+		assert(err == nil)
+		assert(n == 10)
+
+		y, err := p.DecodeInt64(dst[:n])
+		assert(err == nil)
+		assert(x == y)
+	}
+
+	p = NewPacked(Standard, 3, 0)
+	_, err := p.EncodeInt64(dst, 1000)
+	assert(err == ErrOverflow)
+}
+
+func TestPackedBig(t *testing.T) {
+	assert := newAssert(t, true)
+	p := NewPacked(Standard, 30, 4)
+	dst := make([]byte, p.EncodedLen())
+
+	x, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	n, err := p.EncodeBig(dst, x)
+	assert(err == nil)
+	assert(n == 16)
+	assert(dst[0] == 0x01 && dst[15] == 0x0d)
+
+	_, err = p.DecodeInt64(dst)
+	assert(err == ErrOverflow)
+
+	y := new(big.Int)
+	assert(p.DecodeBig(dst, y) == nil)
+	assert(x.Cmp(y) == 0)
+
+	x.Mul(x, big.NewInt(10))
+	_, err = p.EncodeBig(dst, x)
+	assert(err == ErrOverflow)
+}
+
+func ExamplePacked_Encode() {
+	p := NewPacked(Standard, 7, 2)
+
+	dst := make([]byte, p.EncodedLen())
+	n, err := p.Encode(dst, []byte("-12345.67"))
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", dst[:n])
+	// Output: 12 34 56 7d
+}
+
+func ExamplePacked_DecodeInt64() {
+	p := NewPacked(Standard, 7, 2)
+
+	x, err := p.DecodeInt64([]byte{0x00, 0x01, 0x99, 0x5c})
+	if err != nil {
+		return
+	}
+
+	fmt.Println(x)
+	// Output: 1995
+}
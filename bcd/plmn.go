@@ -0,0 +1,290 @@
+package bcd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// PLMNLen is the length of encoded PLMN identity.
+const PLMNLen = 3
+
+// PLMN is Public Land Mobile Network identity which consists of
+// Mobile Country Code (MCC) and Mobile Network Code (MNC) as in 3GPP
+// TS 24.008 clause 10.5.1.3. It is encoded in 3 octets using TBCD
+// digits:
+//
+//	MCC2 MCC1
+//	MNC3 MCC3
+//	MNC2 MNC1
+//
+// where MNC3 is the filler if MNC has 2 digits.
+//
+// PLMN and location identities embedding it implement AppendTo and
+// PruneFrom methods of field.Serializable. As with the identities of
+// package identity, AppendTo appends nothing if PLMN is not valid, so
+// validate it with NewPLMN or ParsePLMN beforehand.
+type PLMN struct {
+	// Mobile Country Code, 3 digits.
+	MCC string
+	// Mobile Network Code, 2 or 3 digits.
+	MNC string
+}
+
+// plmnCodec encodes PLMN digits in the order of MCC1 MCC2 MCC3 MNC3
+// MNC1 MNC2; the filler in place of MNC3 is skipped when decoding.
+var plmnCodec = func() *Codec {
+	c := NewCodec(Telephony)
+	c.IgnoreFiller = true
+	return c
+}()
+
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewPLMN returns PLMN identity of mcc and mnc. ErrBadInput is
+// returned if they are not valid.
+func NewPLMN(mcc, mnc string) (PLMN, error) {
+	p := PLMN{MCC: mcc, MNC: mnc}
+	if !p.Valid() {
+		return PLMN{}, ErrBadInput
+	}
+	return p, nil
+}
+
+// ParsePLMN parses PLMN identity from the string of MCC and MNC
+// digits, e.g. "25001" or "310150".
+func ParsePLMN(s string) (PLMN, error) {
+	if len(s) < 5 {
+		return PLMN{}, ErrBadInput
+	}
+
+	return NewPLMN(s[:3], s[3:])
+}
+
+// DecodePLMN decodes PLMN identity from src.
+func DecodePLMN(src []byte) (PLMN, error) {
+	if len(src) < PLMNLen {
+		return PLMN{}, ErrBadBCD
+	}
+
+	var w [6]byte
+	n, err := plmnCodec.Decode(w[:], src[:PLMNLen])
+	if err != nil {
+		return PLMN{}, err
+	}
+
+	// filler is only allowed in place of MNC3
+	if n < 5 || n == 5 && src[1]>>4 != plmnCodec.Decoder.filler {
+		return PLMN{}, ErrBadBCD
+	}
+
+	s := string(w[:n])
+	p := PLMN{MCC: s[:3], MNC: s[n-2:]}
+	if n == 6 {
+		p.MNC += s[3:4]
+	}
+
+	if !p.Valid() {
+		return PLMN{}, ErrBadBCD
+	}
+	return p, nil
+}
+
+// Valid tells if MCC has 3 and MNC has 2 or 3 decimal digits.
+func (p PLMN) Valid() bool {
+	return isDigits(p.MCC, 3) && (isDigits(p.MNC, 2) || isDigits(p.MNC, 3))
+}
+
+// Encode encodes PLMN identity into dst. Number of encoded bytes and
+// possible error is returned. ErrBadInput is returned if PLMN is not
+// valid, io.ErrShortBuffer is returned if dst is too short.
+func (p PLMN) Encode(dst []byte) (int, error) {
+	if !p.Valid() {
+		return 0, ErrBadInput
+	}
+
+	if len(dst) < PLMNLen {
+		return 0, io.ErrShortBuffer
+	}
+
+	mnc3 := "0"
+	if len(p.MNC) == 3 {
+		mnc3 = p.MNC[2:]
+	}
+
+	var w [6]byte
+	copy(w[:], p.MCC+mnc3+p.MNC[:2])
+	plmnCodec.Encode(dst, w[:])
+	if len(p.MNC) == 2 {
+		dst[1] = plmnCodec.Encoder.filler<<4 | dst[1]&0xf
+	}
+	return PLMNLen, nil
+}
+
+// AppendTo appends encoded PLMN identity to data and returns the
+// resulting slice. Invalid PLMN is not appended.
+func (p PLMN) AppendTo(data []byte) []byte {
+	if !p.Valid() {
+		return data
+	}
+
+	n := len(data)
+	data = grow(data, PLMNLen)
+	p.Encode(data[n:])
+	return data
+}
+
+// PruneFrom decodes PLMN identity from the top of data. It returns
+// remaining data and true if decoding was successful.
+func (p *PLMN) PruneFrom(data []byte) ([]byte, bool) {
+	x, err := DecodePLMN(data)
+	if err != nil {
+		return nil, false
+	}
+	*p = x
+	return data[PLMNLen:], true
+}
+
+// String implements fmt.Stringer interface.
+func (p PLMN) String() string {
+	return p.MCC + p.MNC
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (p PLMN) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (p *PLMN) UnmarshalText(text []byte) (err error) {
+	*p, err = ParsePLMN(string(text))
+	return
+}
+
+// Lengths of encoded location identities.
+const (
+	TAILen  = PLMNLen + 2
+	ECGILen = PLMNLen + 4
+	CGILen  = PLMNLen + 4
+)
+
+// TAI is Tracking Area Identity as in 3GPP TS 23.003 clause 19.4.2.3.
+type TAI struct {
+	PLMN PLMN
+	// Tracking Area Code.
+	TAC uint16
+}
+
+// AppendTo appends encoded TAI to data and returns the resulting
+// slice. TAI with invalid PLMN is not appended.
+func (id TAI) AppendTo(data []byte) []byte {
+	if !id.PLMN.Valid() {
+		return data
+	}
+	data = id.PLMN.AppendTo(data)
+	return append(data, byte(id.TAC>>8), byte(id.TAC))
+}
+
+// PruneFrom decodes TAI from the top of data. It returns remaining
+// data and true if decoding was successful.
+func (id *TAI) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < TAILen {
+		return nil, false
+	}
+	if _, ok := id.PLMN.PruneFrom(data); !ok {
+		return nil, false
+	}
+	id.TAC = binary.BigEndian.Uint16(data[PLMNLen:])
+	return data[TAILen:], true
+}
+
+// String implements fmt.Stringer interface.
+func (id TAI) String() string {
+	return fmt.Sprintf("%s-%d", id.PLMN, id.TAC)
+}
+
+// ECGI is E-UTRAN Cell Global Identifier as in 3GPP TS 23.003 clause
+// 19.6.
+type ECGI struct {
+	PLMN PLMN
+	// E-UTRAN Cell Identifier, 28 bits.
+	ECI uint32
+}
+
+// AppendTo appends encoded ECGI to data and returns the resulting
+// slice. The spare bits of ECI are encoded as zeros. ECGI with
+// invalid PLMN is not appended.
+func (id ECGI) AppendTo(data []byte) []byte {
+	if !id.PLMN.Valid() {
+		return data
+	}
+	data = id.PLMN.AppendTo(data)
+	eci := id.ECI & 0x0fffffff
+	return append(data, byte(eci>>24), byte(eci>>16), byte(eci>>8), byte(eci))
+}
+
+// PruneFrom decodes ECGI from the top of data. It returns remaining
+// data and true if decoding was successful.
+func (id *ECGI) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < ECGILen {
+		return nil, false
+	}
+	if _, ok := id.PLMN.PruneFrom(data); !ok {
+		return nil, false
+	}
+	id.ECI = binary.BigEndian.Uint32(data[PLMNLen:]) & 0x0fffffff
+	return data[ECGILen:], true
+}
+
+// String implements fmt.Stringer interface.
+func (id ECGI) String() string {
+	return fmt.Sprintf("%s-%d", id.PLMN, id.ECI)
+}
+
+// CGI is Cell Global Identification as in 3GPP TS 23.003 clause 4.3.1.
+type CGI struct {
+	PLMN PLMN
+	// Location Area Code.
+	LAC uint16
+	// Cell Identity.
+	CI uint16
+}
+
+// AppendTo appends encoded CGI to data and returns the resulting
+// slice. CGI with invalid PLMN is not appended.
+func (id CGI) AppendTo(data []byte) []byte {
+	if !id.PLMN.Valid() {
+		return data
+	}
+	data = id.PLMN.AppendTo(data)
+	return append(data, byte(id.LAC>>8), byte(id.LAC), byte(id.CI>>8), byte(id.CI))
+}
+
+// PruneFrom decodes CGI from the top of data. It returns remaining
+// data and true if decoding was successful.
+func (id *CGI) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < CGILen {
+		return nil, false
+	}
+	if _, ok := id.PLMN.PruneFrom(data); !ok {
+		return nil, false
+	}
+	id.LAC = binary.BigEndian.Uint16(data[PLMNLen:])
+	id.CI = binary.BigEndian.Uint16(data[PLMNLen+2:])
+	return data[CGILen:], true
+}
+
+// String implements fmt.Stringer interface.
+func (id CGI) String() string {
+	return fmt.Sprintf("%s-%d-%d", id.PLMN, id.LAC, id.CI)
+}
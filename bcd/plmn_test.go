@@ -0,0 +1,136 @@
+package bcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestPLMN(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, test := range []struct {
+		s    string
+		data []byte
+	}{
+		{"25001", []byte{0x52, 0xf0, 0x10}},
+		{"310150", []byte{0x13, 0x00, 0x51}},
+		{"001010", []byte{0x00, 0x01, 0x10}},
+		{"99999", []byte{0x99, 0xf9, 0x99}},
+	} {
+		p, err := ParsePLMN(test.s)
+		assert(err == nil)
+		assert(p.String() == test.s)
+		assert(bytes.Equal(p.AppendTo(nil), test.data))
+
+		p, err = DecodePLMN(test.data)
+		assert(err == nil)
+		assert(p.String() == test.s)
+
+		var q PLMN
+		tail, ok := q.PruneFrom(append(test.data, 0x77))
+		assert(ok && q == p)
+		assert(bytes.Equal(tail, []byte{0x77}))
+	}
+
+	for _, s := range []string{"", "2500", "2500a", "2500123", "25o01"} {
+		_, err := ParsePLMN(s)
+		assert(err == ErrBadInput)
+	}
+
+	for _, data := range [][]byte{
+		{0x52, 0xf0},
+		{0x52, 0xff, 0x10},
+		{0x5a, 0xf0, 0x10},
+		{0x52, 0xf0, 0xf1},
+	} {
+		_, err := DecodePLMN(data)
+		assert(err != nil)
+	}
+
+	_, err := PLMN{MCC: "250", MNC: "1"}.Encode(make([]byte, PLMNLen))
+	assert(err == ErrBadInput)
+
+	_, err = PLMN{MCC: "250", MNC: "01"}.Encode(make([]byte, 2))
+	assert(err == io.ErrShortBuffer)
+
+	p, err := NewPLMN("250", "01")
+	assert(err == nil && p == PLMN{MCC: "250", MNC: "01"})
+	_, err = NewPLMN("250", "1")
+	assert(err == ErrBadInput)
+
+	// invalid PLMN is not appended
+	bad := PLMN{MCC: "25", MNC: "01"}
+	data := []byte{0x77}
+	assert(bytes.Equal(bad.AppendTo(data), data))
+	assert(bytes.Equal(TAI{PLMN: bad}.AppendTo(data), data))
+	assert(bytes.Equal(ECGI{PLMN: bad}.AppendTo(data), data))
+	assert(bytes.Equal(CGI{PLMN: bad}.AppendTo(data), data))
+}
+
+func TestPLMNText(t *testing.T) {
+	assert := newAssert(t, false)
+
+	var v struct{ PLMN PLMN }
+	assert(json.Unmarshal([]byte(`{"PLMN":"310150"}`), &v) == nil)
+	assert(v.PLMN == PLMN{MCC: "310", MNC: "150"})
+
+	b, err := json.Marshal(v)
+	assert(err == nil)
+	assert(string(b) == `{"PLMN":"310150"}`)
+
+	assert(json.Unmarshal([]byte(`{"PLMN":"31"}`), &v) != nil)
+}
+
+func TestLocation(t *testing.T) {
+	assert := newAssert(t, false)
+	plmn := PLMN{MCC: "250", MNC: "01"}
+
+	tai := TAI{PLMN: plmn, TAC: 0x1234}
+	data := tai.AppendTo(nil)
+	assert(len(data) == TAILen)
+	assert(bytes.Equal(data, []byte{0x52, 0xf0, 0x10, 0x12, 0x34}))
+	assert(tai.String() == "25001-4660")
+
+	var tai2 TAI
+	tail, ok := tai2.PruneFrom(data)
+	assert(ok && len(tail) == 0 && tai2 == tai)
+	_, ok = tai2.PruneFrom(data[:TAILen-1])
+	assert(!ok)
+
+	ecgi := ECGI{PLMN: plmn, ECI: 0xf1234567}
+	data = ecgi.AppendTo(nil)
+	assert(len(data) == ECGILen)
+	assert(bytes.Equal(data[PLMNLen:], []byte{0x01, 0x23, 0x45, 0x67}))
+
+	var ecgi2 ECGI
+	tail, ok = ecgi2.PruneFrom(append(data, 0x01))
+	assert(ok && len(tail) == 1)
+	assert(ecgi2.ECI == 0x1234567 && ecgi2.PLMN == plmn)
+
+	cgi := CGI{PLMN: plmn, LAC: 0x0102, CI: 0x0304}
+	data = cgi.AppendTo(nil)
+	assert(len(data) == CGILen)
+	assert(bytes.Equal(data[PLMNLen:], []byte{0x01, 0x02, 0x03, 0x04}))
+	assert(cgi.String() == "25001-258-772")
+
+	var cgi2 CGI
+	tail, ok = cgi2.PruneFrom(data)
+	assert(ok && len(tail) == 0 && cgi2 == cgi)
+
+	data[1] = 0xff
+	_, ok = cgi2.PruneFrom(data)
+	assert(!ok)
+}
+
+func ExampleDecodePLMN() {
+	p, err := DecodePLMN([]byte{0x13, 0x00, 0x51})
+	if err != nil {
+		return
+	}
+
+	fmt.Println(p.MCC, p.MNC)
+	// Output: 310 150
+}
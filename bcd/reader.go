@@ -1,92 +1,176 @@
 package bcd
 
 import (
-	"bytes"
-	// "fmt"
 	"io"
 )
 
+// bufSize is the size of internal buffers of Reader and Writer.
+const bufSize = 4096
+
 // Reader reads encoded BCD data from underlying io.Reader and decodes
 // them. Please pay attention that due to ambiguity of encoding
 // process (encoded octet may indicate the end of data by using the
 // filler nibble) the last input octet is not decoded until the next
 // input octet is observed or until underlying io.Reader returns
 // error.
+//
+// Reader reads the input in large blocks and decodes them at once.
+// Reader implements io.WriterTo so io.Copy from Reader avoids
+// intermediate copying.
 type Reader struct {
 	*Decoder
 	src io.Reader
 	err error
-	buf bytes.Buffer
-	out []byte
+	// encoded octets awaiting decoding
+	in []byte
+	// decoded symbols which didn't fit into the caller's buffer
+	out  word
+	nout int
+	// buffer for WriteTo
+	scratch []byte
+	// number of octets decoded so far
+	pos int
 }
 
 // NewReader creates new Reader with underlying io.Reader.
 func (dec *Decoder) NewReader(rd io.Reader) *Reader {
-	return &Reader{dec, rd, nil, bytes.Buffer{}, []byte{}}
+	return &Reader{Decoder: dec, src: rd, in: make([]byte, 0, bufSize)}
 }
 
-// Read implements io.Reader interface.
-func (r *Reader) Read(p []byte) (n int, err error) {
-	buf := &r.buf
+// Reset discards any buffered data and state, and switches the
+// Reader to read from rd. Reset allows to reuse Reader instances.
+func (r *Reader) Reset(rd io.Reader) {
+	r.src, r.err = rd, nil
+	r.in, r.nout, r.pos = r.in[:0], 0, 0
+}
+
+// unexpected tells if the filler nibble is not expected in the
+// current octet.  The last octet of the stream is denoted by last.
+func (r *Reader) unexpected(last bool) bool {
+	if r.IgnoreFiller {
+		return false
+	}
+	if r.leading {
+		return r.pos != 0
+	}
+	return !last
+}
+
+// fill reads up to x octets from underlying io.Reader. It makes sure
+// there are at least 2 octets available unless an error occurs.
+func (r *Reader) fill(x int) {
+	if x > cap(r.in) {
+		x = cap(r.in)
+	}
 
+	for r.err == nil && len(r.in) < x {
+		var m int
+		m, r.err = r.src.Read(r.in[len(r.in):x])
+		if r.in = r.in[:len(r.in)+m]; len(r.in) >= 2 {
+			break
+		}
+	}
+}
+
+// put decodes octet b into p. The remainder of decoded symbols is
+// saved for the next Read.
+func (r *Reader) put(p []byte, b byte, last bool) (n int, err error) {
+	var w word
+	wid, end, err := r.unpack(w[:], b)
+	if err != nil || end && r.unexpected(last) {
+		// the octet is kept in the input
+		return 0, r.octetError(b, r.pos)
+	}
+	r.pos++
+
+	n = copy(p, w[:wid])
+	r.nout = copy(r.out[:], w[n:wid])
+	return n, nil
+}
+
+// Read implements io.Reader interface. If the input contains invalid
+// octets the error is of type *Error.
+func (r *Reader) Read(p []byte) (n int, err error) {
 	// return previously decoded data first
-	backlog := copy(p[n:], r.out)
-	r.out = r.out[backlog:]
-	n += backlog
+	n = copy(p, r.out[:r.nout])
+	r.nout = copy(r.out[:], r.out[n:r.nout])
 	if len(p) == n {
 		return
 	}
 
-	if x := EncodedLen(len(p)); r.err == nil {
-		// refill on data
-		_, r.err = io.CopyN(buf, r.src, int64(x+1))
-	}
+	// refill on data
+	r.fill(EncodedLen(len(p)-n) + 1)
 
-	if r.err != nil && buf.Len() == 0 {
+	if r.err != nil && len(r.in) == 0 {
 		// underlying Reader gives no data,
 		// buffer is also empty, we're done
 		return n, r.err
 	}
 
-	// decoding buffer
-	w := make([]byte, 2)
+	// the last octet is kept until the end of stream is observed
+	avail := len(r.in)
+	if r.err == nil {
+		avail--
+	}
 
-	// no error yet, we have some data to decode;
-	// decoding until the only byte is left in buffer
-	for buf.Len() > 1 && n < len(p) {
-		b, _ := buf.ReadByte()
-		wid, end, err := r.unpack(w, b)
-		if err != nil {
-			return n, err
-		}
+	// decode as many octets as fit into p at once
+	k := (len(p) - n) / 2
+	if k > avail {
+		k = avail
+	}
 
-		if end && !r.IgnoreFiller {
-			err = ErrBadBCD
+	if k > 0 {
+		last := -1
+		if r.leading && r.pos == 0 {
+			last = 0
+		} else if !r.leading && k == len(r.in) {
+			last = k - 1
 		}
 
-		// fmt.Printf("copying '%c' '%c' - %d bytes\n", w[0], w[1], wid)
-		cp := copy(p[n:], w[:wid])
-		r.out = append(r.out, w[cp:wid]...)
-		n += cp
-
-		if err != nil {
-			return n, err
+		var m int
+		m, err = r.decode(p[n:], r.in[:k], last, r.pos)
+		n += m
+		if e, ok := err.(*Error); ok {
+			// skip only the octets decoded successfully, the
+			// failing octet is kept in the input
+			k = e.Offset - r.pos
 		}
+		r.pos += k
 	}
 
-	// last breath
-	if buf.Len() == 1 && r.err != nil {
-		b, _ := buf.ReadByte()
-		wid, _, err := r.unpack(w, b)
-		if err != nil {
-			return n, err
+	// decode the octet which fits into p partially
+	if err == nil && k < avail && n < len(p) {
+		var cp int
+		cp, err = r.put(p[n:], r.in[k], k == len(r.in)-1)
+		if n += cp; err == nil {
+			k++
 		}
-
-		// fmt.Printf("copying '%c' '%c' - %d bytes\n", w[0], w[1], wid)
-		cp := copy(p[n:], w[:wid])
-		r.out = append(r.out, w[cp:wid]...)
-		n += cp
 	}
 
+	r.in = r.in[:copy(r.in, r.in[k:])]
 	return
 }
+
+// WriteTo implements io.WriterTo interface. It decodes the data from
+// underlying io.Reader until EOF and writes it to w.
+func (r *Reader) WriteTo(w io.Writer) (n int64, err error) {
+	if r.scratch == nil {
+		r.scratch = make([]byte, DecodedLen(bufSize))
+	}
+
+	for {
+		m, err := r.Read(r.scratch)
+		if m > 0 {
+			m, err := w.Write(r.scratch[:m])
+			if n += int64(m); err != nil {
+				return n, err
+			}
+		}
+
+		if err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+	}
+}
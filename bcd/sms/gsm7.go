@@ -0,0 +1,144 @@
+package sms
+
+import (
+	"strings"
+)
+
+// escape is the septet which switches to the extension table.
+const escape = 0x1b
+
+// defaultAlphabet is GSM 7 bit default alphabet as in 3GPP TS 23.038
+// clause 6.2.1 indexed by septet. The escape septet is represented as
+// NUL.
+var defaultAlphabet = []rune("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x00ÆæßÉ" +
+	" !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§" +
+	"¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// extensionTable is GSM 7 bit default alphabet extension table as in
+// 3GPP TS 23.038 clause 6.2.1.1.
+var extensionTable = map[byte]rune{
+	0x0a: '\f', 0x14: '^', 0x28: '{', 0x29: '}', 0x2f: '\\',
+	0x3c: '[', 0x3d: '~', 0x3e: ']', 0x40: '|', 0x65: '€',
+}
+
+var (
+	defaultSeptets   = make(map[rune]byte)
+	extensionSeptets = make(map[rune]byte)
+)
+
+func init() {
+	for i, r := range defaultAlphabet {
+		if i != escape {
+			defaultSeptets[r] = byte(i)
+		}
+	}
+	for c, r := range extensionTable {
+		extensionSeptets[r] = c
+	}
+}
+
+// SeptetLen returns the number of septets needed to encode s in GSM 7
+// bit default alphabet or -1 if s cannot be encoded. The characters
+// of the extension table occupy two septets.
+func SeptetLen(s string) int {
+	n := 0
+	for _, r := range s {
+		if _, ok := defaultSeptets[r]; ok {
+			n++
+		} else if _, ok := extensionSeptets[r]; ok {
+			n += 2
+		} else {
+			return -1
+		}
+	}
+	return n
+}
+
+// EncodeGSM7 encodes s into GSM 7 bit default alphabet and appends
+// the septets to dst, one septet per byte. ErrAlphabet is returned if
+// s contains the characters which cannot be encoded.
+func EncodeGSM7(dst []byte, s string) ([]byte, error) {
+	for _, r := range s {
+		if c, ok := defaultSeptets[r]; ok {
+			dst = append(dst, c)
+		} else if c, ok := extensionSeptets[r]; ok {
+			dst = append(dst, escape, c)
+		} else {
+			return dst, ErrAlphabet
+		}
+	}
+	return dst, nil
+}
+
+// DecodeGSM7 decodes septets of GSM 7 bit default alphabet, one
+// septet per byte. As per 3GPP TS 23.038 the escape followed by the
+// septet missing from the extension table is decoded as that septet
+// of the default alphabet. The trailing escape is ignored.
+func DecodeGSM7(septets []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(septets); i++ {
+		c := septets[i] & 0x7f
+		if c != escape {
+			b.WriteRune(defaultAlphabet[c])
+			continue
+		}
+
+		if i++; i == len(septets) {
+			break
+		}
+
+		c = septets[i] & 0x7f
+		if r, ok := extensionTable[c]; ok {
+			b.WriteRune(r)
+		} else if c != escape {
+			b.WriteRune(defaultAlphabet[c])
+		}
+	}
+	return b.String()
+}
+
+// PackedLen returns the number of octets needed to pack n septets
+// after fill bits.
+func PackedLen(n, fill int) int {
+	return (fill + 7*n + 7) / 8
+}
+
+// Pack packs septets into octets as in 3GPP TS 23.038 clause 6.1.2.1
+// and appends them to dst. Packing starts after fill zero bits, which
+// is used to align septets to the boundary after user data header.
+func Pack(dst, septets []byte, fill int) []byte {
+	var acc uint32
+	bits := uint(fill)
+	for _, c := range septets {
+		acc |= uint32(c&0x7f) << bits
+		for bits += 7; bits >= 8; bits -= 8 {
+			dst = append(dst, byte(acc))
+			acc >>= 8
+		}
+	}
+
+	if bits > 0 {
+		dst = append(dst, byte(acc))
+	}
+	return dst
+}
+
+// Unpack unpacks n septets from src after fill bits and appends them
+// to dst one septet per byte. It returns false if src is too short.
+func Unpack(dst, src []byte, n, fill int) ([]byte, bool) {
+	if len(src) < PackedLen(n, fill) {
+		return dst, false
+	}
+
+	for bit := fill; n > 0; n-- {
+		i, shift := bit/8, uint(bit%8)
+		c := src[i] >> shift
+		if shift > 1 {
+			c |= src[i+1] << (8 - shift)
+		}
+		dst = append(dst, c&0x7f)
+		bit += 7
+	}
+	return dst, true
+}
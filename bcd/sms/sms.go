@@ -0,0 +1,199 @@
+/*
+Package sms implements encoding and decoding of SMS-SUBMIT and
+SMS-DELIVER transfer protocol data units (TPDU) as in 3GPP TS 23.040.
+
+Addresses are encoded in semi-octets using bcd.Telephony table or in
+GSM 7 bit default alphabet if alphanumeric. User data may be encoded
+in GSM 7 bit default alphabet with the extension table, 8 bit data or
+UCS2 as in 3GPP TS 23.038, and may contain user data header, e.g. for
+concatenated messages. Time stamps are encoded with bcdtime package.
+*/
+package sms
+
+import (
+	"fmt"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+// Error values returned by API.
+var (
+	// ErrFormat returned if TPDU is truncated or malformed.
+	ErrFormat = fmt.Errorf("malformed TPDU")
+	// ErrAlphabet returned if text cannot be encoded in the alphabet.
+	ErrAlphabet = fmt.Errorf("text cannot be encoded")
+	// ErrTooLong returned if the field exceeds its maximum length.
+	ErrTooLong = fmt.Errorf("field too long")
+)
+
+// Alphabet is the character set of user data.
+type Alphabet byte
+
+// Alphabets of user data as in 3GPP TS 23.038 clause 4.
+const (
+	AlphabetGSM7 Alphabet = iota
+	Alphabet8Bit
+	AlphabetUCS2
+)
+
+func (a Alphabet) String() string {
+	switch a {
+	case AlphabetGSM7:
+		return "GSM 7 bit"
+	case Alphabet8Bit:
+		return "8 bit data"
+	case AlphabetUCS2:
+		return "UCS2"
+	}
+	return "reserved"
+}
+
+// DCSAlphabet returns the alphabet indicated by TP-Data-Coding-Scheme
+// value. Reserved coding groups and alphabets are treated as 8 bit
+// data.
+func DCSAlphabet(dcs byte) Alphabet {
+	var a Alphabet
+	switch dcs >> 4 {
+	case 0x0, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7:
+		// general data coding, possibly marked for automatic deletion
+		a = Alphabet((dcs >> 2) & 0x3)
+	case 0xc, 0xd:
+		// message waiting indication, GSM 7 bit
+		a = AlphabetGSM7
+	case 0xe:
+		// message waiting indication, UCS2
+		a = AlphabetUCS2
+	case 0xf:
+		// data coding/message class
+		if a = AlphabetGSM7; dcs&0x04 != 0 {
+			a = Alphabet8Bit
+		}
+	default:
+		a = Alphabet8Bit
+	}
+
+	if a > AlphabetUCS2 {
+		a = Alphabet8Bit
+	}
+	return a
+}
+
+// DCS returns TP-Data-Coding-Scheme value of general data coding
+// group with no message class for the alphabet.
+func DCS(a Alphabet) byte {
+	return byte(a&0x3) << 2
+}
+
+// Type of number values of the address.
+const (
+	TONUnknown         byte = 0x0
+	TONInternational   byte = 0x1
+	TONNational        byte = 0x2
+	TONNetworkSpecific byte = 0x3
+	TONSubscriber      byte = 0x4
+	TONAlphanumeric    byte = 0x5
+	TONAbbreviated     byte = 0x6
+)
+
+// Numbering plan identification values of the address.
+const (
+	NPIUnknown  byte = 0x0
+	NPIISDN     byte = 0x1
+	NPIData     byte = 0x3
+	NPITelex    byte = 0x4
+	NPINational byte = 0x8
+	NPIPrivate  byte = 0x9
+	NPIERMES    byte = 0xa
+)
+
+// MaxAddressLen is the maximum length of encoded address value in
+// octets.
+const MaxAddressLen = 10
+
+var tbcd = bcd.NewCodec(bcd.Telephony)
+
+// Address is TP-Originating-Address or TP-Destination-Address as in
+// 3GPP TS 23.040 clause 9.1.2.5.
+type Address struct {
+	// Type of number, 3 bits.
+	TON byte
+	// Numbering plan identification, 4 bits.
+	NPI byte
+	// Value is the address digits or the text of alphanumeric
+	// address. The digits may contain '*', '#', 'a', 'b' and 'c'
+	// symbols.
+	Value string
+}
+
+// AppendTo encodes the address and appends it to dst. The extended
+// slice and possible error is returned.
+func (a Address) AppendTo(dst []byte) ([]byte, error) {
+	toa := 0x80 | (a.TON&0x7)<<4 | a.NPI&0xf
+	if a.TON == TONAlphanumeric {
+		septets, err := EncodeGSM7(nil, a.Value)
+		if err != nil {
+			return dst, err
+		}
+
+		n := PackedLen(len(septets), 0)
+		if n > MaxAddressLen {
+			return dst, ErrTooLong
+		}
+
+		// length is in semi-octets
+		dst = append(dst, byte((7*len(septets)+3)/4), toa)
+		return Pack(dst, septets, 0), nil
+	}
+
+	if bcd.EncodedLen(len(a.Value)) > MaxAddressLen {
+		return dst, ErrTooLong
+	}
+
+	n := len(dst)
+	dst = append(dst, byte(len(a.Value)), toa)
+	dst, err := tbcd.AppendEncode(dst, []byte(a.Value))
+	if err != nil {
+		return dst[:n], err
+	}
+	return dst, nil
+}
+
+// PruneFrom decodes the address from the top of data. It returns
+// remaining data and true if decoding was successful.
+func (a *Address) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < 2 {
+		return nil, false
+	}
+
+	semi, toa := int(data[0]), data[1]
+	n := (semi + 1) / 2
+	if n > MaxAddressLen || len(data) < 2+n {
+		return nil, false
+	}
+
+	src := data[2 : 2+n]
+	a.TON, a.NPI = (toa>>4)&0x7, toa&0xf
+	if a.TON == TONAlphanumeric {
+		septets, _ := Unpack(nil, src, semi*4/7, 0)
+		a.Value = DecodeGSM7(septets)
+		return data[2+n:], true
+	}
+
+	dst := make([]byte, bcd.DecodedLen(n))
+	m, err := tbcd.Decode(dst, src)
+	if err != nil || m != semi {
+		return nil, false
+	}
+
+	a.Value = string(dst[:m])
+	return data[2+n:], true
+}
+
+// String implements fmt.Stringer interface. International number is
+// prefixed with '+'.
+func (a Address) String() string {
+	if a.TON == TONInternational {
+		return "+" + a.Value
+	}
+	return a.Value
+}
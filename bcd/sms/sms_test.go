@@ -0,0 +1,213 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func assert(t *testing.T, expected bool, args ...interface{}) {
+	if t.Helper(); !expected {
+		t.Error(args...)
+	}
+}
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestAlphabet(t *testing.T) {
+	assert(t, len(defaultAlphabet) == 128, len(defaultAlphabet))
+
+	s := "Hello, {World} €5 @home ÄÖÜ"
+	septets, err := EncodeGSM7(nil, s)
+	assert(t, err == nil, err)
+	assert(t, len(septets) == SeptetLen(s), len(septets))
+	assert(t, DecodeGSM7(septets) == s, DecodeGSM7(septets))
+
+	_, err = EncodeGSM7(nil, "привет")
+	assert(t, err == ErrAlphabet, err)
+	assert(t, SeptetLen("привет") == -1)
+
+	// escape followed by unknown septet and trailing escape
+	assert(t, DecodeGSM7([]byte{escape, 'A', 'B', escape}) == "AB")
+}
+
+func TestPack(t *testing.T) {
+	septets, _ := EncodeGSM7(nil, "hellohello")
+	packed := Pack(nil, septets, 0)
+	assert(t, bytes.Equal(packed, unhex("e8329bfd4697d9ec37")), hex.EncodeToString(packed))
+
+	for fill := 0; fill < 7; fill++ {
+		packed := Pack(nil, septets, fill)
+		assert(t, len(packed) == PackedLen(len(septets), fill))
+		unpacked, ok := Unpack(nil, packed, len(septets), fill)
+		assert(t, ok && bytes.Equal(unpacked, septets), fill)
+	}
+
+	_, ok := Unpack(nil, packed[:8], len(septets), 0)
+	assert(t, !ok)
+}
+
+func TestDeliver(t *testing.T) {
+	src := unhex("240dd0e474d81c0ebb010000111011315214000be474d81c0ebb5de3771b")
+	m, err := DecodeDeliver(src)
+	assert(t, err == nil, err)
+	assert(t, !m.MoreMessages && m.StatusReport && !m.ReplyPath)
+	assert(t, m.Originator.TON == TONAlphanumeric, m.Originator)
+	assert(t, m.Originator.Value == "diafaan", m.Originator)
+	assert(t, m.Timestamp.Equal(time.Date(2011, 1, 11, 13, 25, 41, 0, time.UTC)), m.Timestamp)
+
+	text, err := m.Text()
+	assert(t, err == nil && text == "diafaan.com", text)
+
+	out, err := m.AppendTo(nil)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(out, src), hex.EncodeToString(out))
+
+	_, err = DecodeDeliver(src[:len(src)-1])
+	assert(t, err == ErrFormat, err)
+
+	_, err = DecodeSubmit(src)
+	assert(t, err == ErrFormat, err)
+}
+
+func TestSubmit(t *testing.T) {
+	src := unhex("11000b916407281553f80000aa0ae8329bfd4697d9ec37")
+	m, err := DecodeSubmit(src)
+	assert(t, err == nil, err)
+	assert(t, m.Destination.String() == "+46708251358", m.Destination)
+	assert(t, m.VPF == VPFRelative && bytes.Equal(m.VP, []byte{0xaa}))
+	assert(t, RelativeDuration(m.VP[0]) == 4*24*time.Hour)
+
+	text, err := m.Text()
+	assert(t, err == nil && text == "hellohello", text)
+
+	out, err := m.AppendTo(nil)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(out, src), hex.EncodeToString(out))
+
+	m.VP = nil
+	_, err = m.AppendTo(nil)
+	assert(t, err == ErrFormat, err)
+}
+
+func TestUCS2(t *testing.T) {
+	a, sm := EncodeText("Привет 😀")
+	assert(t, a == AlphabetUCS2)
+
+	m := &Submit{
+		Destination: Address{TON: TONNational, NPI: NPIISDN, Value: "9001234567"},
+		DCS:         DCS(a),
+		UserData:    UserData{SM: sm},
+	}
+
+	out, err := m.AppendTo(nil)
+	assert(t, err == nil, err)
+
+	m, err = DecodeSubmit(out)
+	assert(t, err == nil, err)
+	assert(t, DCSAlphabet(m.DCS) == AlphabetUCS2)
+	text, err := m.Text()
+	assert(t, err == nil && text == "Привет 😀", text)
+	assert(t, m.Destination.Value == "9001234567")
+}
+
+func TestSplitText(t *testing.T) {
+	for _, test := range []struct {
+		s     string
+		a     Alphabet
+		parts int
+	}{
+		{"short", AlphabetGSM7, 1},
+		{strings.Repeat("a", 160), AlphabetGSM7, 1},
+		{strings.Repeat("a", 161), AlphabetGSM7, 2},
+		{strings.Repeat("a", 152) + "€" + strings.Repeat("b", 10), AlphabetGSM7, 2},
+		{strings.Repeat("я", 70), AlphabetUCS2, 1},
+		{strings.Repeat("я", 66) + "😀" + strings.Repeat("я", 10), AlphabetUCS2, 2},
+	} {
+		a, parts, err := SplitText(test.s, 0x1234)
+		assert(t, err == nil, err)
+		assert(t, a == test.a && len(parts) == test.parts, test.s)
+
+		var text string
+		for i, ud := range parts {
+			m := &Deliver{
+				Originator: Address{TON: TONInternational, NPI: NPIISDN, Value: "79001234567"},
+				DCS:        DCS(a),
+				Timestamp:  time.Date(2021, 5, 1, 10, 0, 0, 0, time.FixedZone("", 3*3600)),
+				UserData:   ud,
+			}
+
+			out, err := m.AppendTo(nil)
+			assert(t, err == nil, err)
+
+			m, err = DecodeDeliver(out)
+			assert(t, err == nil, err)
+			if len(parts) > 1 {
+				c, ok := m.UserData.Header.Concat()
+				assert(t, ok && c == Concat{0x1234, byte(len(parts)), byte(i + 1)}, c)
+			}
+
+			s, err := m.Text()
+			assert(t, err == nil, err)
+			text += s
+		}
+		assert(t, text == test.s, text)
+	}
+
+	_, _, err := SplitText(strings.Repeat("a", 153*256), 1)
+	assert(t, err == ErrTooLong, err)
+}
+
+func TestHeader(t *testing.T) {
+	h := Header{Concat{Ref: 7, Total: 3, Seq: 2}.IE(), {ID: 0x24, Data: []byte{1}}}
+	data := h.AppendTo(nil)
+	assert(t, bytes.Equal(data, unhex("080003070302240101")), hex.EncodeToString(data))
+
+	var h2 Header
+	rest, ok := h2.PruneFrom(append(data, 0xaa))
+	assert(t, ok && len(rest) == 1 && len(h2) == 2)
+	c, ok := h2.Concat()
+	assert(t, ok && c == Concat{7, 3, 2}, c)
+
+	_, ok = h2.PruneFrom(unhex("0400030703"))
+	assert(t, !ok)
+	// length does not fit into the octet
+	long := Header{{ID: 0x70, Data: make([]byte, 0x100)}}
+	assert(t, long.Validate() == ErrTooLong)
+	assert(t, bytes.Equal(long.AppendTo([]byte{0xaa}), []byte{0xaa}))
+
+	long = Header{{ID: 0x70, Data: make([]byte, 0xff)}, {ID: 0x71}}
+	assert(t, long.Validate() == ErrTooLong)
+	assert(t, h.Validate() == nil)
+
+	ud := UserData{Header: long}
+	_, err := ud.appendTo(nil, Alphabet8Bit)
+	assert(t, err == ErrTooLong, err)
+}
+
+func TestRelativeValidity(t *testing.T) {
+	for _, vp := range []byte{0, 1, 143, 144, 167, 168, 196, 197, 255} {
+		assert(t, RelativeValidity(RelativeDuration(vp)) == vp, vp)
+	}
+	assert(t, RelativeValidity(time.Minute) == 0)
+	assert(t, RelativeValidity(100*7*24*time.Hour) == 0xff)
+}
+
+func TestDCSAlphabet(t *testing.T) {
+	for dcs, a := range map[byte]Alphabet{
+		0x00: AlphabetGSM7, 0x04: Alphabet8Bit, 0x08: AlphabetUCS2,
+		0x0c: Alphabet8Bit, 0x18: AlphabetUCS2, 0x48: AlphabetUCS2,
+		0xc0: AlphabetGSM7, 0xe0: AlphabetUCS2, 0xf0: AlphabetGSM7,
+		0xf4: Alphabet8Bit, 0x80: Alphabet8Bit,
+	} {
+		assert(t, DCSAlphabet(dcs) == a, dcs)
+	}
+}
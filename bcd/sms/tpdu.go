@@ -0,0 +1,280 @@
+package sms
+
+import (
+	"time"
+
+	"github.com/yerden/go-util/bcd/bcdtime"
+)
+
+// TP-Message-Type-Indicator values.
+const (
+	MTIDeliver byte = 0x0
+	MTISubmit  byte = 0x1
+)
+
+// TP-Validity-Period-Format values.
+const (
+	VPFNone     byte = 0x0
+	VPFEnhanced byte = 0x1
+	VPFRelative byte = 0x2
+	VPFAbsolute byte = 0x3
+)
+
+// Bits of the first octet of TPDU.
+const (
+	bitMMS  = 0x04 // TP-More-Messages-to-Send, SMS-DELIVER
+	bitRD   = 0x04 // TP-Reject-Duplicates, SMS-SUBMIT
+	bitLP   = 0x08 // TP-Loop-Prevention, SMS-DELIVER
+	bitSR   = 0x20 // TP-Status-Report-Indication/Request
+	bitUDHI = 0x40 // TP-User-Data-Header-Indicator
+	bitRP   = 0x80 // TP-Reply-Path
+)
+
+// MTI returns TP-Message-Type-Indicator of TPDU. Note that the value
+// depends on the direction, e.g. SMS-DELIVER and SMS-DELIVER-REPORT
+// share the same value.
+func MTI(tpdu []byte) (byte, bool) {
+	if len(tpdu) == 0 {
+		return 0, false
+	}
+	return tpdu[0] & 0x3, true
+}
+
+// Deliver is SMS-DELIVER TPDU sent from service centre to mobile
+// station as in 3GPP TS 23.040 clause 9.2.2.1.
+type Deliver struct {
+	// More messages are waiting in the service centre.
+	MoreMessages bool
+	// Loop prevention.
+	LoopPrevention bool
+	// Status report will be returned to the originator.
+	StatusReport bool
+	// Reply path is set.
+	ReplyPath bool
+
+	// TP-Originating-Address.
+	Originator Address
+	// TP-Protocol-Identifier.
+	PID byte
+	// TP-Data-Coding-Scheme.
+	DCS byte
+	// TP-Service-Centre-Time-Stamp.
+	Timestamp time.Time
+	// TP-User-Data.
+	UserData UserData
+}
+
+// flag returns x if b is true.
+func flag(b bool, x byte) byte {
+	if b {
+		return x
+	}
+	return 0
+}
+
+// AppendTo encodes SMS-DELIVER and appends it to dst. The extended
+// slice and possible error is returned.
+func (m *Deliver) AppendTo(dst []byte) ([]byte, error) {
+	dst = append(dst, MTIDeliver|
+		flag(!m.MoreMessages, bitMMS)|
+		flag(m.LoopPrevention, bitLP)|
+		flag(m.StatusReport, bitSR)|
+		flag(m.UserData.Header != nil, bitUDHI)|
+		flag(m.ReplyPath, bitRP))
+
+	dst, err := m.Originator.AppendTo(dst)
+	if err != nil {
+		return dst, err
+	}
+
+	dst = append(dst, m.PID, m.DCS)
+	if dst, err = bcdtime.AppendSCTS(dst, m.Timestamp); err != nil {
+		return dst, err
+	}
+
+	return m.UserData.appendTo(dst, DCSAlphabet(m.DCS))
+}
+
+// DecodeDeliver decodes SMS-DELIVER from src. Short message of 8 bit
+// data or UCS2 refers to src.
+func DecodeDeliver(src []byte) (*Deliver, error) {
+	if mti, ok := MTI(src); !ok || mti != MTIDeliver {
+		return nil, ErrFormat
+	}
+
+	fo := src[0]
+	m := &Deliver{
+		MoreMessages:   fo&bitMMS == 0,
+		LoopPrevention: fo&bitLP != 0,
+		StatusReport:   fo&bitSR != 0,
+		ReplyPath:      fo&bitRP != 0,
+	}
+
+	data, ok := m.Originator.PruneFrom(src[1:])
+	if !ok || len(data) < 2+bcdtime.SCTSLen {
+		return nil, ErrFormat
+	}
+
+	m.PID, m.DCS = data[0], data[1]
+	ts, err := bcdtime.DecodeSCTS(data[2:])
+	if err != nil {
+		return nil, err
+	}
+	m.Timestamp = ts
+
+	data = data[2+bcdtime.SCTSLen:]
+	if _, ok := m.UserData.pruneFrom(data, DCSAlphabet(m.DCS), fo&bitUDHI != 0); !ok {
+		return nil, ErrFormat
+	}
+	return m, nil
+}
+
+// Text returns the text of short message. See UserData.Text.
+func (m *Deliver) Text() (string, error) {
+	return m.UserData.Text(DCSAlphabet(m.DCS))
+}
+
+// Submit is SMS-SUBMIT TPDU sent from mobile station to service
+// centre as in 3GPP TS 23.040 clause 9.2.2.2.
+type Submit struct {
+	// Service centre should reject duplicates.
+	RejectDuplicates bool
+	// Status report is requested.
+	StatusReport bool
+	// Reply path is set.
+	ReplyPath bool
+
+	// TP-Message-Reference.
+	MR byte
+	// TP-Destination-Address.
+	Destination Address
+	// TP-Protocol-Identifier.
+	PID byte
+	// TP-Data-Coding-Scheme.
+	DCS byte
+	// TP-Validity-Period-Format.
+	VPF byte
+	// TP-Validity-Period. It is 1 octet long if VPF is VPFRelative, 7
+	// octets long if VPF is VPFAbsolute or VPFEnhanced, and empty
+	// otherwise. See RelativeValidity and AbsoluteValidity.
+	VP []byte
+	// TP-User-Data.
+	UserData UserData
+}
+
+// vpLen returns the length of TP-Validity-Period of the format.
+func vpLen(vpf byte) int {
+	switch vpf {
+	case VPFRelative:
+		return 1
+	case VPFAbsolute, VPFEnhanced:
+		return 7
+	}
+	return 0
+}
+
+// AppendTo encodes SMS-SUBMIT and appends it to dst. The extended
+// slice and possible error is returned.
+func (m *Submit) AppendTo(dst []byte) ([]byte, error) {
+	if len(m.VP) != vpLen(m.VPF) {
+		return dst, ErrFormat
+	}
+
+	dst = append(dst, MTISubmit|
+		flag(m.RejectDuplicates, bitRD)|
+		(m.VPF&0x3)<<3|
+		flag(m.StatusReport, bitSR)|
+		flag(m.UserData.Header != nil, bitUDHI)|
+		flag(m.ReplyPath, bitRP), m.MR)
+
+	dst, err := m.Destination.AppendTo(dst)
+	if err != nil {
+		return dst, err
+	}
+
+	dst = append(dst, m.PID, m.DCS)
+	dst = append(dst, m.VP...)
+	return m.UserData.appendTo(dst, DCSAlphabet(m.DCS))
+}
+
+// DecodeSubmit decodes SMS-SUBMIT from src. Validity period and short
+// message of 8 bit data or UCS2 refer to src.
+func DecodeSubmit(src []byte) (*Submit, error) {
+	if mti, ok := MTI(src); !ok || mti != MTISubmit || len(src) < 2 {
+		return nil, ErrFormat
+	}
+
+	fo := src[0]
+	m := &Submit{
+		RejectDuplicates: fo&bitRD != 0,
+		VPF:              (fo >> 3) & 0x3,
+		StatusReport:     fo&bitSR != 0,
+		ReplyPath:        fo&bitRP != 0,
+		MR:               src[1],
+	}
+
+	data, ok := m.Destination.PruneFrom(src[2:])
+	n := 2 + vpLen(m.VPF)
+	if !ok || len(data) < n {
+		return nil, ErrFormat
+	}
+
+	m.PID, m.DCS = data[0], data[1]
+	if m.VPF != VPFNone {
+		m.VP = data[2:n]
+	}
+
+	if _, ok := m.UserData.pruneFrom(data[n:], DCSAlphabet(m.DCS), fo&bitUDHI != 0); !ok {
+		return nil, ErrFormat
+	}
+	return m, nil
+}
+
+// Text returns the text of short message. See UserData.Text.
+func (m *Submit) Text() (string, error) {
+	return m.UserData.Text(DCSAlphabet(m.DCS))
+}
+
+// RelativeValidity returns relative TP-Validity-Period which is the
+// closest to d not exceeding it as in 3GPP TS 23.040 clause
+// 9.2.3.12.1. The minimum period is 5 minutes and the maximum period
+// is 63 weeks.
+func RelativeValidity(d time.Duration) byte {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+
+	switch {
+	case d < 5*time.Minute:
+		return 0
+	case d <= 12*time.Hour:
+		return byte(d/(5*time.Minute) - 1)
+	case d <= day:
+		return byte(143 + (d-12*time.Hour)/(30*time.Minute))
+	case d <= 30*day:
+		return byte(166 + d/day)
+	case d <= 63*week:
+		return byte(192 + d/week)
+	}
+	return 0xff
+}
+
+// RelativeDuration returns the duration of relative TP-Validity-Period.
+func RelativeDuration(vp byte) time.Duration {
+	x := time.Duration(vp)
+	switch {
+	case vp <= 143:
+		return (x + 1) * 5 * time.Minute
+	case vp <= 167:
+		return 12*time.Hour + (x-143)*30*time.Minute
+	case vp <= 196:
+		return (x - 166) * 24 * time.Hour
+	}
+	return (x - 192) * 7 * 24 * time.Hour
+}
+
+// AbsoluteValidity returns absolute TP-Validity-Period of t.
+func AbsoluteValidity(t time.Time) ([]byte, error) {
+	return bcdtime.AppendSCTS(nil, t)
+}
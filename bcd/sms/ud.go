@@ -0,0 +1,318 @@
+package sms
+
+import (
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// Information element identifiers of user data header.
+const (
+	// Concatenated short messages, 8 bit reference number.
+	IEIConcat8 byte = 0x00
+	// Concatenated short messages, 16 bit reference number.
+	IEIConcat16 byte = 0x08
+)
+
+// Maximum length of user data.
+const (
+	// MaxUDOctets is the maximum length of user data in octets.
+	MaxUDOctets = 140
+	// MaxUDSeptets is the maximum length of user data in septets.
+	MaxUDSeptets = 160
+)
+
+// IE is the information element of user data header.
+type IE struct {
+	// Information element identifier.
+	ID byte
+	// Information element data.
+	Data []byte
+}
+
+// Header is user data header as in 3GPP TS 23.040 clause 9.2.3.24.
+type Header []IE
+
+// Len returns the length of encoded header including the length
+// octet.
+func (h Header) Len() int {
+	n := 1
+	for _, ie := range h {
+		n += 2 + len(ie.Data)
+	}
+	return n
+}
+
+// Validate returns ErrTooLong if the header or the data of its
+// information element does not fit into the length octet.
+func (h Header) Validate() error {
+	for _, ie := range h {
+		if len(ie.Data) > 0xff {
+			return ErrTooLong
+		}
+	}
+	if h.Len()-1 > 0xff {
+		return ErrTooLong
+	}
+	return nil
+}
+
+// AppendTo appends encoded header to dst and returns the resulting
+// slice. Invalid header is not appended, see Validate.
+func (h Header) AppendTo(dst []byte) []byte {
+	if h.Validate() != nil {
+		return dst
+	}
+
+	dst = append(dst, byte(h.Len()-1))
+	for _, ie := range h {
+		dst = append(dst, ie.ID, byte(len(ie.Data)))
+		dst = append(dst, ie.Data...)
+	}
+	return dst
+}
+
+// PruneFrom decodes the header from the top of data. Data of
+// information elements refer to data. It returns remaining data and
+// true if decoding was successful.
+func (h *Header) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return nil, false
+	}
+
+	src := data[1 : 1+data[0]]
+	*h = (*h)[:0]
+	for len(src) > 0 {
+		if len(src) < 2 || len(src) < 2+int(src[1]) {
+			return nil, false
+		}
+		*h = append(*h, IE{src[0], src[2 : 2+src[1]]})
+		src = src[2+src[1]:]
+	}
+	return data[1+data[0]:], true
+}
+
+// Concat is the part of concatenated short message.
+type Concat struct {
+	// Reference number of concatenated message.
+	Ref uint16
+	// Total number of parts.
+	Total byte
+	// Sequence number of the part starting from 1.
+	Seq byte
+}
+
+// IE returns the information element of the part. The element with
+// 16 bit reference number is used if Ref does not fit into 8 bits.
+func (c Concat) IE() IE {
+	if c.Ref > 0xff {
+		return IE{IEIConcat16, []byte{byte(c.Ref >> 8), byte(c.Ref), c.Total, c.Seq}}
+	}
+	return IE{IEIConcat8, []byte{byte(c.Ref), c.Total, c.Seq}}
+}
+
+// Concat returns the concatenated message part of the header.
+func (h Header) Concat() (c Concat, ok bool) {
+	for _, ie := range h {
+		switch {
+		case ie.ID == IEIConcat8 && len(ie.Data) == 3:
+			return Concat{uint16(ie.Data[0]), ie.Data[1], ie.Data[2]}, true
+		case ie.ID == IEIConcat16 && len(ie.Data) == 4:
+			ref := binary.BigEndian.Uint16(ie.Data)
+			return Concat{ref, ie.Data[2], ie.Data[3]}, true
+		}
+	}
+	return Concat{}, false
+}
+
+// UserData is TP-User-Data.
+type UserData struct {
+	// Header is the user data header or nil if missing.
+	Header Header
+
+	// Short message in the alphabet specified by the data coding
+	// scheme: unpacked septets of GSM 7 bit default alphabet, one
+	// septet per byte, 8 bit data or UTF-16 encoded text for UCS2.
+	SM []byte
+}
+
+// fillBits returns the number of fill bits after the header of n
+// octets which align following septets to the septet boundary.
+func fillBits(n int) int {
+	return (7 - 8*n%7) % 7
+}
+
+// appendTo appends TP-User-Data-Length and TP-User-Data encoded in
+// the alphabet to dst.
+func (ud *UserData) appendTo(dst []byte, a Alphabet) ([]byte, error) {
+	var hdr int
+	if ud.Header != nil {
+		if err := ud.Header.Validate(); err != nil {
+			return dst, err
+		}
+		hdr = ud.Header.Len()
+	}
+
+	if a != AlphabetGSM7 {
+		if hdr+len(ud.SM) > MaxUDOctets {
+			return dst, ErrTooLong
+		}
+
+		dst = append(dst, byte(hdr+len(ud.SM)))
+		if ud.Header != nil {
+			dst = ud.Header.AppendTo(dst)
+		}
+		return append(dst, ud.SM...), nil
+	}
+
+	fill := fillBits(hdr)
+	udl := (8*hdr+fill)/7 + len(ud.SM)
+	if udl > MaxUDSeptets {
+		return dst, ErrTooLong
+	}
+
+	dst = append(dst, byte(udl))
+	if ud.Header != nil {
+		dst = ud.Header.AppendTo(dst)
+	}
+	return Pack(dst, ud.SM, fill), nil
+}
+
+// pruneFrom decodes TP-User-Data-Length and TP-User-Data encoded in
+// the alphabet from the top of data. If hasHeader is true the user
+// data contains the header.
+func (ud *UserData) pruneFrom(data []byte, a Alphabet, hasHeader bool) ([]byte, bool) {
+	if len(data) < 1 {
+		return nil, false
+	}
+
+	udl := int(data[0])
+	n := udl
+	if a == AlphabetGSM7 {
+		n = PackedLen(udl, 0)
+	}
+
+	if len(data) < 1+n {
+		return nil, false
+	}
+
+	src, rest := data[1:1+n], data[1+n:]
+	ud.Header = nil
+	hdr := 0
+	if hasHeader {
+		tail, ok := ud.Header.PruneFrom(src)
+		if !ok {
+			return nil, false
+		}
+		hdr, src = len(src)-len(tail), tail
+	}
+
+	if a != AlphabetGSM7 {
+		ud.SM = src
+		return rest, true
+	}
+
+	fill := fillBits(hdr)
+	septets := udl - (8*hdr+fill)/7
+	if septets < 0 {
+		return nil, false
+	}
+
+	sm, ok := Unpack(nil, src, septets, fill)
+	if !ok {
+		return nil, false
+	}
+	ud.SM = sm
+	return rest, true
+}
+
+// Text decodes short message of the alphabet into string. ErrAlphabet
+// is returned for 8 bit data.
+func (ud *UserData) Text(a Alphabet) (string, error) {
+	switch a {
+	case AlphabetGSM7:
+		return DecodeGSM7(ud.SM), nil
+	case AlphabetUCS2:
+		return decodeUCS2(ud.SM), nil
+	}
+	return "", ErrAlphabet
+}
+
+func decodeUCS2(src []byte) string {
+	u := make([]uint16, len(src)/2)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(src[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+func appendUCS2(dst []byte, s []rune) []byte {
+	for _, u := range utf16.Encode(s) {
+		dst = append(dst, byte(u>>8), byte(u))
+	}
+	return dst
+}
+
+// EncodeText encodes s into short message. GSM 7 bit default alphabet
+// is used if possible, UCS2 otherwise. The chosen alphabet and the
+// short message is returned.
+func EncodeText(s string) (Alphabet, []byte) {
+	if sm, err := EncodeGSM7(nil, s); err == nil {
+		return AlphabetGSM7, sm
+	}
+	return AlphabetUCS2, appendUCS2(nil, []rune(s))
+}
+
+// SplitText encodes s into user data of one or more short messages.
+// If the text does not fit into single message it is split into
+// concatenated message parts with the reference number ref. The
+// characters are never split between the parts. The chosen alphabet
+// and user data of the parts is returned. ErrTooLong is returned if
+// the text requires more than 255 parts.
+func SplitText(s string, ref uint16) (Alphabet, []UserData, error) {
+	a, sm := EncodeText(s)
+	max := MaxUDSeptets
+	if a == AlphabetUCS2 {
+		max = MaxUDOctets
+	}
+
+	if len(sm) <= max {
+		return a, []UserData{{SM: sm}}, nil
+	}
+
+	// room for the header of concatenated message part
+	hdr := Header{Concat{Ref: ref}.IE()}.Len()
+	if a == AlphabetGSM7 {
+		max -= (8*hdr + fillBits(hdr)) / 7
+	} else {
+		// UTF-16 code units are not split
+		max = (max - hdr) &^ 1
+	}
+
+	var parts []UserData
+	for len(sm) > 0 {
+		n := len(sm)
+		if n > max {
+			n = max
+			switch {
+			case a == AlphabetGSM7 && sm[n-1] == escape:
+				// escape and extension character
+				n--
+			case a == AlphabetUCS2 && sm[n-2]&0xfc == 0xd8:
+				// high surrogate
+				n -= 2
+			}
+		}
+		parts = append(parts, UserData{SM: sm[:n]})
+		sm = sm[n:]
+	}
+
+	if len(parts) > 0xff {
+		return a, nil, ErrTooLong
+	}
+
+	for i := range parts {
+		c := Concat{ref, byte(len(parts)), byte(i + 1)}
+		parts[i].Header = Header{c.IE()}
+	}
+	return a, parts, nil
+}
@@ -0,0 +1,123 @@
+package bcd
+
+import (
+	"bufio"
+)
+
+// isFillerOctet tells if both nibbles of b are fillers.
+func (dec *Decoder) isFillerOctet(b byte) bool {
+	return b == dec.filler<<4|dec.filler
+}
+
+// splitFiller finds the first filler-delimited record at the
+// beginning of data. It returns the length of the record, the number
+// of octets to skip after it and true if the end of the record was
+// found.
+func (dec *Decoder) splitFiller(data []byte) (n, skip int, ok bool) {
+	if !dec.leading {
+		for i, b := range data {
+			switch {
+			case dec.hashWord[b][2] == 0:
+				continue
+			case dec.isFillerOctet(b):
+				return i, 1, true
+			}
+			// either filler octet or invalid one
+			return i + 1, 0, true
+		}
+		return len(data), 0, false
+	}
+
+	for i := 1; i < len(data); i++ {
+		if dec.startsRecord(data[i]) {
+			return i, 0, true
+		}
+	}
+	return len(data), 0, false
+}
+
+// startsRecord tells if b starts filler-delimited record if the
+// filler is leading.
+func (dec *Decoder) startsRecord(b byte) bool {
+	return dec.hashWord[b][2] != 0 && dec.hashByte[b][1] == 0 || dec.isFillerOctet(b)
+}
+
+// SplitFillerFunc returns bufio.SplitFunc which splits the stream of
+// BCD encoded records delimited by the filler nibble and yields the
+// decoded records. If the filler is trailing the record ends with the
+// octet containing the filler. If the filler is leading the record
+// starts with it. Even-length records are expected to be delimited
+// by the octet which consists of the filler nibbles only. Empty
+// records, e.g. consecutive octets of filler nibbles, are skipped.
+//
+// The filler nibble should not be mapped to any symbol. IgnoreFiller
+// setting is not used. Decoding errors are of type *Error with the
+// offset counted from the beginning of the record.
+//
+// The SplitFunc keeps no state so it may be shared by several
+// bufio.Scanner.
+func (dec *Decoder) SplitFillerFunc() bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if dec.leading && len(data) > 0 && dec.isFillerOctet(data[0]) {
+			// even-length record starts after filler octet
+			return 1, nil, nil
+		}
+
+		n, skip, ok := dec.splitFiller(data)
+		if !ok && (!atEOF || n == 0) {
+			// request more data
+			return 0, nil, nil
+		}
+
+		return dec.splitRecord(data[:n], skip)
+	}
+}
+
+// splitRecord decodes the record for bufio.SplitFunc, then skip
+// octets are skipped. Empty record yields no token.
+func (dec *Decoder) splitRecord(record []byte, skip int) (advance int, token []byte, err error) {
+	if len(record) == 0 {
+		return skip, nil, nil
+	}
+
+	token = make([]byte, DecodedLen(len(record)))
+	n, err := dec.decode(token, record, dec.fillerAt(len(record)), 0)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if n == 0 {
+		return len(record) + skip, nil, nil
+	}
+	return len(record) + skip, token[:n], nil
+}
+
+// SplitFixedFunc returns bufio.SplitFunc which splits the stream of
+// BCD encoded records each of n octets length and yields the decoded
+// records. Each record is decoded as if by Decode. Incomplete
+// record at the end of the stream is decoded as well. Empty records
+// are skipped.
+//
+// Decoding errors are of type *Error with the offset counted from the
+// beginning of the record.
+//
+// The SplitFunc keeps no state so it may be shared by several
+// bufio.Scanner.
+func (dec *Decoder) SplitFixedFunc(n int) bufio.SplitFunc {
+	if n <= 0 {
+		panic("invalid record length")
+	}
+
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		k := n
+		if len(data) < k {
+			if !atEOF || len(data) == 0 {
+				// request more data
+				return 0, nil, nil
+			}
+			k = len(data)
+		}
+
+		return dec.splitRecord(data[:k], 0)
+	}
+}
@@ -0,0 +1,124 @@
+package bcd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/iotest"
+)
+
+func scanAll(split bufio.SplitFunc, src []byte) (tokens []string, err error) {
+	s := bufio.NewScanner(iotest.OneByteReader(bytes.NewReader(src)))
+	s.Split(split)
+	for s.Scan() {
+		tokens = append(tokens, s.Text())
+	}
+	return tokens, s.Err()
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSplitFiller(t *testing.T) {
+	assert := newAssert(t, false)
+	dec := NewDecoder(Telephony)
+
+	src := []byte{
+		0x21, 0xf3, // 123
+		0x54, 0xf6, // 456
+		0x87, 0xff, // 78
+		0xf9, // 9
+		0x01, // 10
+	}
+	tokens, err := scanAll(dec.SplitFillerFunc(), src)
+	assert(err == nil)
+	assert(equalStrings(tokens, []string{"123", "456", "78", "9", "10"}))
+
+	tokens, err = scanAll(dec.SplitFillerFunc(), []byte{0x21, 0xf3, 0x54, 0xe6, 0x8f})
+	assert(equalStrings(tokens, []string{"123"}))
+	e, ok := err.(*Error)
+	assert(ok && errors.Is(err, ErrBadBCD))
+	assert(e.Offset == 2 && e.Reason == UnexpectedFiller)
+
+	// empty records are skipped everywhere
+	for _, src := range [][]byte{
+		{0xff, 0x21, 0xf3, 0xff, 0xff, 0x54, 0xf6, 0xff},
+		{0x21, 0xf3, 0xff, 0x54, 0xf6},
+	} {
+		tokens, err = scanAll(dec.SplitFillerFunc(), src)
+		assert(err == nil)
+		assert(equalStrings(tokens, []string{"123", "456"}))
+	}
+
+	dec = NewDecoder(leading)
+	src = []byte{
+		0xf1, 0x23, // 123
+		0xf4, 0x56, // 456
+		0xff, 0x78, // 78
+		0xf9, 0x10, // 910
+	}
+	tokens, err = scanAll(dec.SplitFillerFunc(), src)
+	assert(err == nil)
+	assert(equalStrings(tokens, []string{"123", "456", "78", "910"}))
+
+	tokens, err = scanAll(dec.SplitFillerFunc(), []byte{0xff, 0xff, 0xf1, 0x23, 0xff})
+	assert(err == nil)
+	assert(equalStrings(tokens, []string{"123"}))
+}
+
+func TestSplitShared(t *testing.T) {
+	assert := newAssert(t, false)
+	split := NewDecoder(Telephony).SplitFillerFunc()
+
+	s1 := bufio.NewScanner(bytes.NewReader([]byte{0x21, 0xf3, 0x54, 0xf6}))
+	s2 := bufio.NewScanner(bytes.NewReader([]byte{0x87, 0xff, 0x21, 0xf3}))
+	s1.Split(split)
+	s2.Split(split)
+
+	var tokens []string
+	for s1.Scan() && s2.Scan() {
+		tokens = append(tokens, s1.Text(), s2.Text())
+	}
+	assert(s1.Err() == nil && s2.Err() == nil)
+	assert(equalStrings(tokens, []string{"123", "78", "456", "123"}))
+}
+
+func TestSplitFixed(t *testing.T) {
+	assert := newAssert(t, false)
+	dec := NewDecoder(Telephony)
+
+	src := []byte{0x21, 0xf3, 0x54, 0x76, 0x98}
+	tokens, err := scanAll(dec.SplitFixedFunc(2), src)
+	assert(err == nil)
+	assert(equalStrings(tokens, []string{"123", "4567", "89"}))
+
+	_, err = scanAll(dec.SplitFixedFunc(2), []byte{0x21, 0x43, 0xf5, 0x76})
+	e, ok := err.(*Error)
+	assert(ok && e.Offset == 0)
+}
+
+func ExampleDecoder_SplitFillerFunc() {
+	dec := NewDecoder(Telephony)
+	src := []byte{0x21, 0xf3, 0x54, 0x76, 0xf8, 0x09, 0xff}
+
+	s := bufio.NewScanner(bytes.NewReader(src))
+	s.Split(dec.SplitFillerFunc())
+	for s.Scan() {
+		fmt.Println(s.Text())
+	}
+	// Output:
+	// 123
+	// 45678
+	// 90
+}
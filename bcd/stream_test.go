@@ -0,0 +1,166 @@
+package bcd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+func randomDigits(rnd *rand.Rand, n int) []byte {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = '0' + byte(rnd.Intn(10))
+	}
+	return s
+}
+
+func TestStreamBulk(t *testing.T) {
+	assert := newAssert(t, true)
+	rnd := rand.New(rand.NewSource(1))
+	codec := NewCodec(Telephony)
+
+	for _, size := range []int{0, 1, 2, bufSize - 1, bufSize, 2*bufSize + 1, 5*bufSize + 3} {
+		src := randomDigits(rnd, size)
+		expected, err := codec.AppendEncode(nil, src)
+		assert(err == nil)
+
+		// io.Copy takes ReadFrom path
+		encoded := new(bytes.Buffer)
+		w := codec.NewWriter(encoded)
+		n, err := io.Copy(w, bytes.NewReader(src))
+		assert(err == nil && n == int64(size))
+		assert(w.Flush() == nil)
+		assert(bytes.Equal(encoded.Bytes(), expected))
+
+		// io.Copy takes WriteTo path
+		decoded := new(bytes.Buffer)
+		r := codec.NewReader(bytes.NewReader(expected))
+		n, err = io.Copy(decoded, r)
+		assert(err == nil && n == int64(size))
+		assert(bytes.Equal(decoded.Bytes(), src))
+
+		// read with odd sized buffers
+		decoded.Reset()
+		r.Reset(iotest.HalfReader(bytes.NewReader(expected)))
+		p := make([]byte, 7)
+		for {
+			m, err := r.Read(p)
+			decoded.Write(p[:m])
+			if err == io.EOF {
+				break
+			}
+			assert(err == nil)
+		}
+		assert(bytes.Equal(decoded.Bytes(), src))
+	}
+}
+
+func TestStreamReset(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+
+	dst := new(bytes.Buffer)
+	w := codec.NewWriter(ioutil.Discard)
+	w.Write([]byte("123"))
+	w.Reset(dst)
+	assert(w.Buffered() == 0)
+	w.Write([]byte("45"))
+	assert(dst.Len() == 0)
+	assert(w.Flush() == nil)
+	assert(bytes.Equal(dst.Bytes(), []byte{0x54}))
+
+	r := codec.NewReader(bytes.NewReader([]byte{0xff}))
+	_, err := ioutil.ReadAll(r)
+	assert(errors.Is(err, ErrBadBCD))
+
+	r.Reset(bytes.NewReader([]byte{0x21, 0xf3}))
+	out, err := ioutil.ReadAll(r)
+	assert(err == nil)
+	assert(string(out) == "123")
+}
+
+func TestStreamWriteError(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+
+	failure := errors.New("failure")
+	w := codec.NewWriter(failingWriter{failure})
+	_, err := w.Write(bytes.Repeat([]byte("12"), bufSize+1))
+	assert(err == failure)
+
+	_, err = w.Write([]byte("12"))
+	assert(err == failure)
+	assert(w.Flush() == failure)
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func BenchmarkStreamEncode(b *testing.B) {
+	src := randomDigits(rand.New(rand.NewSource(1)), 1<<20)
+	rd := bytes.NewReader(src)
+	w := NewEncoder(Telephony).NewWriter(ioutil.Discard)
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		rd.Reset(src)
+		w.Reset(ioutil.Discard)
+		io.Copy(w, rd)
+		w.Flush()
+	}
+}
+
+func BenchmarkStreamDecode(b *testing.B) {
+	codec := NewCodec(Telephony)
+	src, _ := codec.AppendEncode(nil, randomDigits(rand.New(rand.NewSource(1)), 1<<20))
+	rd := bytes.NewReader(src)
+	r := codec.NewReader(rd)
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		rd.Reset(src)
+		r.Reset(rd)
+		io.Copy(ioutil.Discard, r)
+	}
+}
+
+func TestStreamReadErrorKeepsOctet(t *testing.T) {
+	assert := newAssert(t, false)
+	codec := NewCodec(Telephony)
+	src := []byte{0x21, 0x43, 0xff, 0x65, 0x87, 0x09}
+
+	for _, size := range []int{3, 4, 16} {
+		r := codec.NewReader(bytes.NewReader(src))
+		p := make([]byte, size)
+
+		var out []byte
+		var err error
+		for err == nil {
+			var n int
+			n, err = r.Read(p)
+			out = append(out, p[:n]...)
+		}
+		assert(string(out) == "1234")
+
+		// the failing octet is not skipped
+		for i := 0; i < 2; i++ {
+			e, ok := err.(*Error)
+			assert(ok && e.Offset == 2 && e.Byte == 0xff)
+
+			var n int
+			n, err = r.Read(p)
+			assert(n == 0)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package bcd
+
+import (
+	"encoding/json"
+)
+
+var telephony = NewCodec(Telephony)
+
+// String is the string of symbols which is BCD encoded with the
+// codec. It may be declared as a field of protocol structures. The
+// encoded string occupies all available data; see FixedString,
+// PrefixedString and TerminatedString for the variants which may be
+// followed by other fields.
+//
+// String implements AppendTo and PruneFrom methods of
+// field.Serializable. AppendTo will panic if the string contains
+// symbols which cannot be encoded.
+//
+// The zero value is an empty string encoded with Telephony table.
+type String struct {
+	// Codec used to encode and decode the string. If nil, Telephony
+	// is used.
+	Codec *Codec
+
+	// Value is the decoded string.
+	Value string
+}
+
+func (s *String) codec() *Codec {
+	if s.Codec == nil {
+		return telephony
+	}
+	return s.Codec
+}
+
+// check returns an error if v cannot be encoded.
+func (s *String) check(v string) error {
+	enc := &s.codec().Encoder
+	for i := 0; i < len(v); i++ {
+		if enc.hash[v[i]] > 0xf {
+			return enc.symbolError([]byte{v[i]}, i)
+		}
+	}
+	return nil
+}
+
+// set decodes src into the value.
+func (s *String) set(src []byte) error {
+	dec := &s.codec().Decoder
+	dst := make([]byte, DecodedLen(len(src)))
+	n, err := dec.decode(dst, src, dec.fillerAt(len(src)), 0)
+	if err != nil {
+		return err
+	}
+	s.Value = string(dst[:n])
+	return nil
+}
+
+// EncodedLen returns the length of the encoded string.
+func (s String) EncodedLen() int {
+	return EncodedLen(len(s.Value))
+}
+
+// String implements fmt.Stringer interface.
+func (s String) String() string {
+	return s.Value
+}
+
+// AppendTo appends encoded string to data and returns the resulting
+// slice.
+func (s String) AppendTo(data []byte) []byte {
+	data, err := s.codec().AppendEncode(data, []byte(s.Value))
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// PruneFrom decodes the string from all of data. It returns empty
+// remaining data and true if decoding was successful.
+func (s *String) PruneFrom(data []byte) ([]byte, bool) {
+	if s.set(data) != nil {
+		return nil, false
+	}
+	return data[len(data):], true
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (s String) MarshalBinary() ([]byte, error) {
+	return s.codec().AppendEncode(nil, []byte(s.Value))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (s *String) UnmarshalBinary(data []byte) error {
+	return s.set(data)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s String) MarshalText() ([]byte, error) {
+	if err := s.check(s.Value); err != nil {
+		return nil, err
+	}
+	return []byte(s.Value), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. The
+// text should consist of symbols which can be encoded.
+func (s *String) UnmarshalText(text []byte) error {
+	v := string(text)
+	if err := s.check(v); err != nil {
+		return err
+	}
+	s.Value = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (s String) MarshalJSON() ([]byte, error) {
+	if err := s.check(s.Value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (s *String) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(v))
+}
+
+// FixedString is String encoded in the fixed number of octets. The
+// shorter string is padded with the octets consisting of filler
+// nibbles which are stripped on decoding.
+type FixedString struct {
+	String
+
+	// Len is the length of encoded string in octets.
+	Len int
+}
+
+// fillerOctets returns the number of trailing octets of src which
+// consist of filler nibbles only.
+func (dec *Decoder) fillerOctets(src []byte) int {
+	n := 0
+	for i := len(src) - 1; i >= 0 && dec.isFillerOctet(src[i]); i-- {
+		n++
+	}
+	return n
+}
+
+// AppendTo appends encoded string to data and returns the resulting
+// slice. It panics if the string does not fit into Len octets.
+func (s FixedString) AppendTo(data []byte) []byte {
+	data, err := s.appendTo(data)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func (s FixedString) appendTo(data []byte) ([]byte, error) {
+	n := s.EncodedLen()
+	if n > s.Len {
+		return data, ErrOverflow
+	}
+
+	data, err := s.codec().AppendEncode(data, []byte(s.Value))
+	if err != nil {
+		return data, err
+	}
+
+	filler := s.codec().Encoder.filler
+	for ; n < s.Len; n++ {
+		data = append(data, filler<<4|filler)
+	}
+	return data, nil
+}
+
+// PruneFrom decodes the string from Len octets at the top of data.
+// It returns remaining data and true if decoding was successful.
+func (s *FixedString) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < s.Len || s.UnmarshalBinary(data[:s.Len]) != nil {
+		return nil, false
+	}
+	return data[s.Len:], true
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (s FixedString) MarshalBinary() ([]byte, error) {
+	return s.appendTo(nil)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// The data should be exactly Len octets long.
+func (s *FixedString) UnmarshalBinary(data []byte) error {
+	if len(data) != s.Len {
+		return ErrBadBCD
+	}
+
+	n := s.codec().fillerOctets(data)
+	return s.set(data[:len(data)-n])
+}
+
+// PrefixedString is String encoded after one octet which holds the
+// length of encoded string in octets.
+type PrefixedString struct {
+	String
+}
+
+// maxPrefixedLen is the maximum length of PrefixedString in symbols.
+const maxPrefixedLen = 2 * 0xff
+
+// AppendTo appends the length and encoded string to data and returns
+// the resulting slice. It panics if the string is too long.
+func (s PrefixedString) AppendTo(data []byte) []byte {
+	data, err := s.appendTo(data)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func (s PrefixedString) appendTo(data []byte) ([]byte, error) {
+	if len(s.Value) > maxPrefixedLen {
+		return data, ErrOverflow
+	}
+
+	data = append(data, byte(s.EncodedLen()))
+	return s.codec().AppendEncode(data, []byte(s.Value))
+}
+
+// PruneFrom decodes the length and the string from the top of data.
+// It returns remaining data and true if decoding was successful.
+func (s *PrefixedString) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	n := 1 + int(data[0])
+	if len(data) < n || s.set(data[1:n]) != nil {
+		return nil, false
+	}
+	return data[n:], true
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (s PrefixedString) MarshalBinary() ([]byte, error) {
+	return s.appendTo(nil)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (s *PrefixedString) UnmarshalBinary(data []byte) error {
+	rest, ok := s.PruneFrom(data)
+	if !ok || len(rest) != 0 {
+		return ErrBadBCD
+	}
+	return nil
+}
+
+// TerminatedString is String delimited by the filler nibble as in
+// SplitFillerFunc. If the filler is trailing, the string of even
+// length is followed by the octet consisting of filler nibbles. If
+// the filler is leading, such octet precedes the string and the end
+// of the string is the beginning of the next octet with the filler
+// nibble or the end of data.
+type TerminatedString struct {
+	String
+}
+
+// AppendTo appends encoded string with the filler to data and
+// returns the resulting slice.
+func (s TerminatedString) AppendTo(data []byte) []byte {
+	data, err := s.appendTo(data)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func (s TerminatedString) appendTo(data []byte) ([]byte, error) {
+	enc := &s.codec().Encoder
+	filler := enc.filler<<4 | enc.filler
+	even := len(s.Value)%2 == 0
+
+	if even && enc.leading {
+		data = append(data, filler)
+	}
+
+	data, err := enc.AppendEncode(data, []byte(s.Value))
+	if err == nil && even && !enc.leading {
+		data = append(data, filler)
+	}
+	return data, err
+}
+
+// PruneFrom decodes the string from the top of data. It returns
+// remaining data and true if decoding was successful.
+func (s *TerminatedString) PruneFrom(data []byte) ([]byte, bool) {
+	dec := &s.codec().Decoder
+	if dec.leading && len(data) > 0 && dec.isFillerOctet(data[0]) {
+		data = data[1:]
+		if len(data) > 0 && dec.startsRecord(data[0]) {
+			// empty string
+			s.Value = ""
+			return data, true
+		}
+	}
+
+	n, skip, ok := dec.splitFiller(data)
+	if !ok && !dec.leading {
+		return nil, false
+	}
+
+	if s.set(data[:n]) != nil {
+		return nil, false
+	}
+	return data[n+skip:], true
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (s TerminatedString) MarshalBinary() ([]byte, error) {
+	return s.appendTo(nil)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (s *TerminatedString) UnmarshalBinary(data []byte) error {
+	rest, ok := s.PruneFrom(data)
+	if !ok || len(rest) != 0 {
+		return ErrBadBCD
+	}
+	return nil
+}
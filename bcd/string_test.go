@@ -0,0 +1,163 @@
+package bcd
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// serializable mirrors field.Serializable interface.
+type serializable interface {
+	AppendTo([]byte) []byte
+	PruneFrom([]byte) ([]byte, bool)
+}
+
+var (
+	_ serializable               = (*String)(nil)
+	_ serializable               = (*FixedString)(nil)
+	_ serializable               = (*PrefixedString)(nil)
+	_ serializable               = (*TerminatedString)(nil)
+	_ encoding.BinaryMarshaler   = String{}
+	_ encoding.BinaryUnmarshaler = (*String)(nil)
+	_ encoding.TextMarshaler     = String{}
+	_ encoding.TextUnmarshaler   = (*String)(nil)
+	_ json.Marshaler             = String{}
+	_ json.Unmarshaler           = (*String)(nil)
+)
+
+func TestString(t *testing.T) {
+	assert := newAssert(t, false)
+
+	s := String{Value: "12345"}
+	data := s.AppendTo([]byte{0x77})
+	assert(bytes.Equal(data, []byte{0x77, 0x21, 0x43, 0xf5}))
+
+	var s2 String
+	rest, ok := s2.PruneFrom(data[1:])
+	assert(ok && len(rest) == 0 && s2.Value == "12345")
+
+	b, err := s.MarshalBinary()
+	assert(err == nil && bytes.Equal(b, data[1:]))
+
+	s2 = String{Codec: NewCodec(Standard)}
+	assert(s2.UnmarshalBinary([]byte{0x12, 0x3f}) == nil)
+	assert(s2.String() == "123")
+	_, ok = s2.PruneFrom([]byte{0x1a})
+	assert(!ok)
+
+	_, err = String{Value: "12x"}.MarshalBinary()
+	assert(errors.Is(err, ErrBadInput))
+
+	_, err = String{Value: "12x"}.MarshalText()
+	var e *Error
+	assert(errors.As(err, &e) && e.Offset == 2 && e.Byte == 'x')
+}
+
+func TestStringJSON(t *testing.T) {
+	assert := newAssert(t, false)
+
+	var v struct {
+		MSISDN String
+		Card   FixedString
+	}
+	v.Card.Len = 4
+
+	assert(json.Unmarshal([]byte(`{"MSISDN":"79001234567","Card":"*100#"}`), &v) == nil)
+	assert(v.MSISDN.Value == "79001234567" && v.Card.Value == "*100#")
+
+	b, err := json.Marshal(v)
+	assert(err == nil)
+	assert(string(b) == `{"MSISDN":"79001234567","Card":"*100#"}`)
+
+	assert(json.Unmarshal([]byte(`{"MSISDN":"7900x"}`), &v) != nil)
+	assert(json.Unmarshal([]byte(`{"MSISDN":7900}`), &v) != nil)
+}
+
+func TestFixedString(t *testing.T) {
+	assert := newAssert(t, false)
+
+	s := FixedString{String{Value: "123"}, 4}
+	data := s.AppendTo(nil)
+	assert(bytes.Equal(data, []byte{0x21, 0xf3, 0xff, 0xff}))
+
+	s2 := FixedString{Len: 4}
+	rest, ok := s2.PruneFrom(append(data, 0x01))
+	assert(ok && s2.Value == "123")
+	assert(bytes.Equal(rest, []byte{0x01}))
+
+	_, ok = s2.PruneFrom(data[:3])
+	assert(!ok)
+	assert(s2.UnmarshalBinary(data[:3]) == ErrBadBCD)
+
+	s.Value = "123456789"
+	_, err := s.MarshalBinary()
+	assert(err == ErrOverflow)
+
+	s.Value = "12345678"
+	data, err = s.MarshalBinary()
+	assert(err == nil && len(data) == 4)
+	assert(s2.UnmarshalBinary(data) == nil && s2.Value == s.Value)
+}
+
+func TestPrefixedString(t *testing.T) {
+	assert := newAssert(t, false)
+
+	s := PrefixedString{String{Value: "1234"}}
+	data := s.AppendTo(nil)
+	assert(bytes.Equal(data, []byte{0x02, 0x21, 0x43}))
+
+	var s2 PrefixedString
+	rest, ok := s2.PruneFrom(append(data, 0x01))
+	assert(ok && s2.Value == "1234")
+	assert(bytes.Equal(rest, []byte{0x01}))
+
+	_, ok = s2.PruneFrom(data[:2])
+	assert(!ok)
+	assert(s2.UnmarshalBinary(append(data, 0x01)) == ErrBadBCD)
+
+	s.Value = string(make([]byte, maxPrefixedLen+1))
+	_, err := s.MarshalBinary()
+	assert(err == ErrOverflow)
+}
+
+func TestTerminatedString(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, config := range []*BCD{Telephony, leading} {
+		c := NewCodec(config)
+		var data []byte
+		values := []string{"123", "1234", "", "5"}
+		for _, v := range values {
+			data = TerminatedString{String{c, v}}.AppendTo(data)
+		}
+
+		s := TerminatedString{String{Codec: c}}
+		for _, v := range values {
+			var ok bool
+			data, ok = s.PruneFrom(data)
+			assert(ok && s.Value == v)
+		}
+		assert(len(data) == 0)
+	}
+
+	var s TerminatedString
+	_, ok := s.PruneFrom([]byte{0x21, 0x43})
+	assert(!ok)
+	assert(s.UnmarshalBinary([]byte{0x21, 0x43, 0xff}) == nil)
+	assert(s.Value == "1234")
+}
+
+func ExampleFixedString() {
+	s := FixedString{String{Value: "*100#"}, 4}
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", data)
+	// Output: 1a 00 fb ff
+}
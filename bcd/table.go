@@ -0,0 +1,238 @@
+package bcd
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrBadTable returned if BCD table cannot be parsed or is invalid.
+var ErrBadTable = fmt.Errorf("invalid BCD table")
+
+var registry = struct {
+	sync.RWMutex
+	tables map[string]*BCD
+}{tables: map[string]*BCD{
+	"standard":  Standard,
+	"8421":      Standard,
+	"excess3":   Excess3,
+	"telephony": Telephony,
+	"tbcd":      Telephony,
+	"aiken":     Aiken,
+	"2421":      Aiken,
+	"5421":      Code5421,
+	"4221":      Code4221,
+	"gray":      Gray,
+}}
+
+// Register makes BCD table available by name. Names are case
+// insensitive. The error is returned if the name is already taken or
+// the table is invalid. The table should not be modified after
+// registration.
+func Register(name string, config *BCD) error {
+	if !checkBCD(config) {
+		return ErrBadTable
+	}
+
+	name = strings.ToLower(name)
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.tables[name]; ok {
+		return fmt.Errorf("%w: name %q is taken", ErrBadTable, name)
+	}
+	registry.tables[name] = config
+	return nil
+}
+
+// Lookup returns BCD table registered by name. Predefined tables are
+// registered by the following names:
+//
+//	standard, 8421  - Standard
+//	excess3         - Excess3
+//	telephony, tbcd - Telephony
+//	aiken, 2421     - Aiken
+//	5421            - Code5421
+//	4221            - Code4221
+//	gray            - Gray
+func Lookup(name string) (*BCD, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	config, ok := registry.tables[strings.ToLower(name)]
+	return config, ok
+}
+
+// Names returns sorted names of registered BCD tables.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.tables))
+	for name := range registry.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Special characters of the text form of BCD table.
+const (
+	unmapped  = '.'
+	separator = ';'
+)
+
+// ParseBCD parses text form of BCD table. The text form consists of
+// the symbols string followed by options separated by semicolon. The
+// i-th symbol of the string is encoded by the nibble i, the dot
+// stands for the unmapped nibble. Trailing unmapped nibbles may be
+// omitted. The options are:
+//
+//	swap       - SwapNibbles is set
+//	leading    - LeadingFiller is set
+//	filler=X   - Filler is the hexadecimal nibble X, 0xf by default
+//	alias=XxYy - symbols X and Y are aliases of x and y
+//
+// For example, Telephony table is represented as:
+//
+//	0123456789*#abc;swap;filler=f;alias=AaBbCc
+//
+// The filler should not be mapped to any symbol.
+func ParseBCD(s string) (*BCD, error) {
+	opts := strings.Split(s, string(separator))
+	syms := opts[0]
+	if len(syms) == 0 || len(syms) > 0x10 {
+		return nil, fmt.Errorf("%w: symbols %q", ErrBadTable, syms)
+	}
+
+	config := &BCD{Map: make(map[byte]byte), Filler: 0xf}
+	for i := 0; i < len(syms); i++ {
+		c := syms[i]
+		if c == unmapped {
+			continue
+		}
+		if _, ok := config.Map[c]; ok || c == separator || c < ' ' || c > '~' {
+			return nil, fmt.Errorf("%w: symbol %q", ErrBadTable, c)
+		}
+		config.Map[c] = byte(i)
+	}
+
+	for _, opt := range opts[1:] {
+		key, value := opt, ""
+		if i := strings.IndexByte(opt, '='); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+
+		var err error
+		switch key {
+		case "swap":
+			config.SwapNibbles = true
+		case "leading":
+			config.LeadingFiller = true
+		case "filler":
+			var x uint64
+			x, err = strconv.ParseUint(value, 16, 4)
+			config.Filler = byte(x)
+		case "alias":
+			if len(value)%2 != 0 {
+				err = ErrBadTable
+				break
+			}
+			config.Aliases = make(map[byte]byte)
+			for i := 0; i < len(value); i += 2 {
+				config.Aliases[value[i]] = value[i+1]
+			}
+		default:
+			err = ErrBadTable
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: option %q", ErrBadTable, opt)
+		}
+	}
+
+	if nib := config.Filler; nib < byte(len(syms)) && syms[nib] != unmapped {
+		return nil, fmt.Errorf("%w: filler %x is mapped", ErrBadTable, nib)
+	}
+
+	if !checkBCD(config) {
+		return nil, ErrBadTable
+	}
+	return config, nil
+}
+
+// String returns text form of the BCD table. See ParseBCD for
+// details.
+func (config BCD) String() string {
+	var syms [0x10]byte
+	for i := range syms {
+		syms[i] = unmapped
+	}
+	for c, nib := range config.Map {
+		if nib <= 0xf {
+			syms[nib] = c
+		}
+	}
+
+	var b strings.Builder
+	b.Write(bytes.TrimRight(syms[:], string(unmapped)))
+	if config.SwapNibbles {
+		b.WriteString(";swap")
+	}
+	if config.LeadingFiller {
+		b.WriteString(";leading")
+	}
+	fmt.Fprintf(&b, ";filler=%x", config.Filler)
+
+	if len(config.Aliases) > 0 {
+		aliases := make([]string, 0, len(config.Aliases))
+		for alias, c := range config.Aliases {
+			aliases = append(aliases, string([]byte{alias, c}))
+		}
+		sort.Strings(aliases)
+		b.WriteString(";alias=")
+		b.WriteString(strings.Join(aliases, ""))
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler interface. It
+// returns text form of the BCD table.
+func (config BCD) MarshalText() ([]byte, error) {
+	if !checkBCD(&config) {
+		return nil, ErrBadTable
+	}
+	return []byte(config.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. The
+// text is either the name of registered BCD table or the text form
+// of the table as in ParseBCD. The registered table is copied, so
+// modifying config does not affect it.
+func (config *BCD) UnmarshalText(text []byte) error {
+	if c, ok := Lookup(string(text)); ok {
+		*config = *c
+		config.Map = copyMap(c.Map)
+		config.Aliases = copyMap(c.Aliases)
+		return nil
+	}
+
+	c, err := ParseBCD(string(text))
+	if err != nil {
+		return err
+	}
+	*config = *c
+	return nil
+}
+
+func copyMap(m map[byte]byte) map[byte]byte {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[byte]byte, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,127 @@
+package bcd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, name := range []string{"standard", "TBCD", "Aiken", "5421", "4221", "gray"} {
+		config, ok := Lookup(name)
+		assert(ok)
+
+		// all digits encode and decode back
+		c := NewCodec(config)
+		dst := make([]byte, 5)
+		n, err := c.Encode(dst, []byte("0123456789"))
+		assert(err == nil && n == 5)
+		x, err := c.DecodeUint64(dst)
+		assert(err == nil && x == 123456789)
+	}
+
+	_, ok := Lookup("unknown")
+	assert(!ok)
+
+	custom := &BCD{Map: Standard.Map, Filler: 0xa}
+	assert(Register("Custom", custom) == nil)
+	config, ok := Lookup("custom")
+	assert(ok && config == custom)
+	assert(errors.Is(Register("CUSTOM", custom), ErrBadTable))
+	assert(Register("bad", &BCD{Filler: 0x10}) == ErrBadTable)
+
+	names := Names()
+	for i := 1; i < len(names); i++ {
+		assert(names[i-1] < names[i])
+	}
+}
+
+func TestAliases(t *testing.T) {
+	assert := newAssert(t, false)
+	c := NewCodec(Telephony)
+
+	dst := make([]byte, 2)
+	n, err := c.Encode(dst, []byte("1Ab"))
+	assert(err == nil && n == 2)
+	assert(dst[0] == 0xc1 && dst[1] == 0xfd)
+
+	out := make([]byte, 4)
+	n, err = c.Decode(out, dst)
+	assert(err == nil && string(out[:n]) == "1ab")
+
+	assert(!checkBCD(&BCD{Map: Standard.Map, Aliases: map[byte]byte{'1': '2'}}))
+	assert(!checkBCD(&BCD{Map: Standard.Map, Aliases: map[byte]byte{'a': 'b'}}))
+}
+
+func TestParseBCD(t *testing.T) {
+	assert := newAssert(t, false)
+
+	for _, config := range []*BCD{Standard, Excess3, Telephony, Aiken, Code5421, Code4221, Gray, leading} {
+		s := config.String()
+		parsed, err := ParseBCD(s)
+		assert(err == nil)
+		assert(parsed.String() == s)
+	}
+
+	assert(Telephony.String() == "0123456789*#abc;swap;filler=f;alias=AaBbCc")
+	assert(Aiken.String() == "01234......56789;filler=5")
+
+	for _, s := range []string{
+		"",
+		"0123456789abcdefg",
+		"0113456789",
+		"0123456789;filler=1",
+		"0123456789abcdef",
+		"0123456789;filler=x",
+		"0123456789;filler=10",
+		"0123456789;swapped",
+		"0123456789;alias=A",
+		"0123456789;alias=ab",
+	} {
+		_, err := ParseBCD(s)
+		assert(errors.Is(err, ErrBadTable))
+	}
+}
+
+func TestBCDJSON(t *testing.T) {
+	assert := newAssert(t, false)
+
+	var v struct {
+		A, B BCD
+	}
+	assert(json.Unmarshal([]byte(`{"A":"telephony","B":"0123456789;leading;filler=a"}`), &v) == nil)
+	assert(v.A.SwapNibbles && v.A.Aliases['C'] == 'c')
+	assert(v.B.LeadingFiller && v.B.Filler == 0xa && len(v.B.Map) == 10)
+
+	b, err := json.Marshal(v)
+	assert(err == nil)
+	assert(string(b) == `{"A":"0123456789*#abc;swap;filler=f;alias=AaBbCc","B":"0123456789;leading;filler=a"}`)
+
+	assert(json.Unmarshal([]byte(`{"A":"0123"}`), &v) == nil)
+	assert(json.Unmarshal([]byte(`{"A":"01234567890"}`), &v) != nil)
+	// registered table is not affected
+	assert(json.Unmarshal([]byte(`{"A":"tbcd"}`), &v) == nil)
+	v.A.Map['x'] = 0xf
+	v.A.Aliases['x'] = 'a'
+	delete(v.A.Map, '0')
+	_, ok := Telephony.Map['x']
+	assert(!ok && Telephony.Map['0'] == 0x0 && len(Telephony.Map) == 15)
+	_, ok = Telephony.Aliases['x']
+	assert(!ok)
+}
+
+func ExampleParseBCD() {
+	config, err := ParseBCD("9876543210;filler=f")
+	if err != nil {
+		return
+	}
+
+	dst := make([]byte, 2)
+	enc := NewEncoder(config)
+	n, _ := enc.Encode(dst, []byte("123"))
+	fmt.Printf("% x\n", dst[:n])
+	// Output: 87 6f
+}
@@ -0,0 +1,120 @@
+package bcd
+
+import (
+	"golang.org/x/text/transform"
+)
+
+// Transform implements transform.Transformer interface. The odd
+// trailing symbol of src is encoded only if atEOF is true.
+//
+// If the filler is leading the alignment of encoded data depends on
+// the total length of the input, so no data is encoded until atEOF
+// is true. Please note that in that case the whole input should fit
+// into the buffer of transform.Reader or transform.Writer.
+//
+// Errors other than transform.ErrShortDst and transform.ErrShortSrc
+// are of type *Error with the offset counted from the beginning of
+// src.
+func (enc *Encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	if enc.leading && len(src) > 0 {
+		if !atEOF {
+			return 0, 0, transform.ErrShortSrc
+		}
+
+		if len(src)%2 != 0 {
+			// filler goes to the first octet
+			if len(dst) == 0 {
+				return 0, 0, transform.ErrShortDst
+			}
+			if _, dst[0], err = enc.pack(src[:1]); err != nil {
+				return 0, 0, enc.symbolError(src, 0)
+			}
+			nDst, nSrc = 1, 1
+		}
+	}
+
+	for nSrc < len(src) {
+		w := src[nSrc:]
+		if len(w) == 1 && !atEOF {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		wid, b, err := enc.pack(w)
+		if err != nil {
+			return nDst, nSrc, enc.symbolError(w, nSrc)
+		}
+
+		dst[nDst] = b
+		nDst++
+		nSrc += wid
+	}
+
+	return nDst, nSrc, nil
+}
+
+// Reset implements transform.Transformer interface. Encoder keeps
+// no state so Reset does nothing.
+func (enc *Encoder) Reset() {}
+
+// Transform implements transform.Transformer interface. The octet
+// with the filler nibble at the end of src is decoded only if atEOF
+// is true.
+//
+// Decoder keeps no state between the calls, so it may be shared by
+// simultaneous transformations. If the filler is leading it is only
+// allowed in the first octet of the input, so no data is decoded
+// until atEOF is true and the whole output fits into dst. Please note
+// that in that case the whole input should fit into the buffer of
+// transform.Reader or transform.Writer.
+//
+// Errors other than transform.ErrShortDst and transform.ErrShortSrc
+// are of type *Error with the offset counted from the beginning of
+// src.
+func (dec *Decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	leading := dec.leading && !dec.IgnoreFiller
+	if leading && len(src) > 0 && !atEOF {
+		return 0, 0, transform.ErrShortSrc
+	}
+
+	var w word
+	for i, b := range src {
+		wid, end, err := dec.unpack(w[:], b)
+		if err != nil {
+			return nDst, nSrc, dec.octetError(b, i)
+		}
+
+		if end && !dec.IgnoreFiller {
+			switch {
+			case dec.leading && i != 0:
+				return nDst, nSrc, dec.octetError(b, i)
+			case dec.leading:
+			case i != len(src)-1:
+				return nDst, nSrc, dec.octetError(b, i)
+			case !atEOF:
+				// is it the end of data?
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+		}
+
+		if len(dst)-nDst < wid {
+			if leading {
+				// the rest of input would lose its first octet
+				return 0, 0, transform.ErrShortDst
+			}
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		nDst += copy(dst[nDst:], w[:wid])
+		nSrc++
+	}
+
+	return nDst, nSrc, nil
+}
+
+// Reset implements transform.Transformer interface. Decoder keeps
+// no state so Reset does nothing.
+func (dec *Decoder) Reset() {}
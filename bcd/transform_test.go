@@ -0,0 +1,177 @@
+package bcd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+func TestEncoderTransform(t *testing.T) {
+	assert := newAssert(t, false)
+	enc := NewEncoder(Telephony)
+	dst := make([]byte, 10)
+
+	nDst, nSrc, err := enc.Transform(dst, []byte("12345"), false)
+	assert(err == transform.ErrShortSrc)
+	assert(nDst == 2 && nSrc == 4)
+	assert(bytes.Equal(dst[:nDst], []byte{0x21, 0x43}))
+
+	nDst, nSrc, err = enc.Transform(dst, []byte("12345"), true)
+	assert(err == nil)
+	assert(nDst == 3 && nSrc == 5)
+	assert(bytes.Equal(dst[:nDst], []byte{0x21, 0x43, 0xf5}))
+
+	nDst, nSrc, err = enc.Transform(dst[:1], []byte("12345"), true)
+	assert(err == transform.ErrShortDst)
+	assert(nDst == 1 && nSrc == 2)
+
+	_, nSrc, err = enc.Transform(dst, []byte("12x45"), true)
+	e, ok := err.(*Error)
+	assert(ok && errors.Is(err, ErrBadInput))
+	assert(e.Offset == 2 && nSrc == 2)
+
+	enc = NewEncoder(leading)
+	nDst, nSrc, err = enc.Transform(dst, []byte("123"), false)
+	assert(err == transform.ErrShortSrc)
+	assert(nDst == 0 && nSrc == 0)
+
+	nDst, nSrc, err = enc.Transform(dst, []byte("123"), true)
+	assert(err == nil)
+	assert(nSrc == 3)
+	assert(bytes.Equal(dst[:nDst], []byte{0xf1, 0x23}))
+}
+
+func TestDecoderTransform(t *testing.T) {
+	assert := newAssert(t, false)
+	dec := NewDecoder(Telephony)
+	dst := make([]byte, 10)
+
+	nDst, nSrc, err := dec.Transform(dst, []byte{0x21, 0x43, 0xf5}, false)
+	assert(err == transform.ErrShortSrc)
+	assert(nSrc == 2 && string(dst[:nDst]) == "1234")
+
+	nDst, nSrc, err = dec.Transform(dst, []byte{0x21, 0x43, 0xf5}, true)
+	assert(err == nil)
+	assert(nSrc == 3 && string(dst[:nDst]) == "12345")
+
+	nDst, nSrc, err = dec.Transform(dst[:3], []byte{0x21, 0x43, 0xf5}, true)
+	assert(err == transform.ErrShortDst)
+	assert(nSrc == 1 && string(dst[:nDst]) == "12")
+
+	_, _, err = dec.Transform(dst, []byte{0x21, 0xf3, 0xf5}, true)
+	e, ok := err.(*Error)
+	assert(ok && e.Offset == 1 && e.Reason == UnexpectedFiller)
+
+	dec = NewDecoder(leading)
+	nDst, nSrc, err = dec.Transform(dst, []byte{0xf1, 0x23}, false)
+	assert(err == transform.ErrShortSrc)
+	assert(nDst == 0 && nSrc == 0)
+
+	nDst, nSrc, err = dec.Transform(dst, []byte{0xf1, 0x23}, true)
+	assert(err == nil)
+	assert(nSrc == 2 && string(dst[:nDst]) == "123")
+
+	// the rest of input would start with the filler
+	nDst, nSrc, err = dec.Transform(dst[:2], []byte{0xf1, 0x23}, true)
+	assert(err == transform.ErrShortDst)
+	assert(nDst == 0 && nSrc == 0)
+
+	_, _, err = dec.Transform(dst, []byte{0x12, 0xf4}, true)
+	e, ok = err.(*Error)
+	assert(ok && e.Offset == 1)
+}
+
+func TestTransformReader(t *testing.T) {
+	assert := newAssert(t, true)
+	rnd := rand.New(rand.NewSource(1))
+	codec := NewCodec(Telephony)
+
+	for _, size := range []int{0, 1, 2, 4095, 4096, 10001} {
+		src := randomDigits(rnd, size)
+		expected, _ := codec.AppendEncode(nil, src)
+
+		r := transform.NewReader(iotest.OneByteReader(bytes.NewReader(src)), &codec.Encoder)
+		encoded, err := ioutil.ReadAll(r)
+		assert(err == nil)
+		assert(bytes.Equal(encoded, expected))
+
+		r = transform.NewReader(bytes.NewReader(encoded), &codec.Decoder)
+		decoded, err := ioutil.ReadAll(r)
+		assert(err == nil)
+		assert(bytes.Equal(decoded, src))
+	}
+
+	// round trip
+	chain := transform.Chain(NewEncoder(Aiken), NewDecoder(Aiken))
+	s, _, err := transform.String(chain, "12345")
+	assert(err == nil)
+	assert(s == "12345")
+
+	// leading filler
+	chain = transform.Chain(NewEncoder(leading), NewDecoder(leading))
+	for _, src := range []string{"", "1", "12", "12345", string(randomDigits(rnd, 1001))} {
+		s, _, err = transform.String(chain, src)
+		assert(err == nil)
+		assert(s == src)
+	}
+}
+
+func ExampleEncoder_Transform() {
+	enc := NewEncoder(Telephony)
+
+	dst, _, err := transform.Bytes(enc, []byte("12345"))
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("% x\n", dst)
+	// Output: 21 43 f5
+}
+
+func TestTransformConcurrent(t *testing.T) {
+	codec := NewCodec(Telephony)
+	src := []byte{0x21, 0x43, 0x65, 0x87, 0xf9}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s, _, err := transform.Bytes(&codec.Decoder, src)
+				if err == nil && string(s) != "123456789" {
+					err = fmt.Errorf("decoded %q", s)
+				}
+
+				_, _, e := transform.Bytes(&codec.Decoder, []byte{0x21, 0xf3, 0x45})
+				if e, ok := e.(*Error); err == nil && (!ok || e.Offset != 1) {
+					err = fmt.Errorf("unexpected error %v", e)
+				}
+
+				d, _, e := transform.Bytes(&codec.Encoder, []byte("12345"))
+				if err == nil && (e != nil || !bytes.Equal(d, []byte{0x21, 0x43, 0xf5})) {
+					err = fmt.Errorf("encoded % x, %v", d, e)
+				}
+
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,70 @@
+package bcd
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeWide(t *testing.T) {
+	assert := newAssert(t, true)
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, config := range []*BCD{Standard, Excess3, Telephony, Aiken, leading} {
+		for _, ignore := range []bool{false, true} {
+			dec := NewDecoder(config)
+			dec.IgnoreFiller = ignore
+
+			for k := 0; k < 1000; k++ {
+				src := make([]byte, rnd.Intn(64))
+				rnd.Read(src)
+				if k%2 == 0 {
+					// mostly valid input
+					enc := NewEncoder(config)
+					src, _ = enc.AppendEncode(src[:0], randomDigits(rnd, rnd.Intn(128)))
+					if len(src) > 0 && k%4 == 0 {
+						src[rnd.Intn(len(src))] = byte(rnd.Intn(0x100))
+					}
+				}
+
+				dst1 := make([]byte, rnd.Intn(2*len(src)+2))
+				dst2 := make([]byte, len(dst1))
+				last := dec.fillerAt(len(src))
+				n1, err1 := dec.decode(dst1, src, last, 3)
+				n2, err2 := dec.decodeTable(dst2, src, last, 3)
+				assert(n1 == n2)
+				assert(bytes.Equal(dst1[:n1], dst2[:n2]))
+				assert(reflect.DeepEqual(err1, err2))
+			}
+		}
+	}
+}
+
+// optInput is the input of BenchmarkDecodeOpt.
+var optInput = []byte{0x21, 0x43, 0x65, 0x87}
+
+// benchmarkDecode decodes the input of BenchmarkDecodeOpt repeated
+// to n octets with the same decoder either in blocks or with the
+// lookup table only.
+func benchmarkDecode(b *testing.B, n int, table bool) {
+	dec := NewDecoder(enc)
+	src := bytes.Repeat(optInput, n/len(optInput))
+	dst := make([]byte, DecodedLen(len(src)))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		if table {
+			dec.decodeTable(dst, src, len(src)-1, 0)
+		} else {
+			dec.Decode(dst, src)
+		}
+	}
+}
+
+func BenchmarkDecodeWide4(b *testing.B)     { benchmarkDecode(b, 4, false) }
+func BenchmarkDecodeTable4(b *testing.B)    { benchmarkDecode(b, 4, true) }
+func BenchmarkDecodeWide16(b *testing.B)    { benchmarkDecode(b, 16, false) }
+func BenchmarkDecodeTable16(b *testing.B)   { benchmarkDecode(b, 16, true) }
+func BenchmarkDecodeWide4096(b *testing.B)  { benchmarkDecode(b, 4096, false) }
+func BenchmarkDecodeTable4096(b *testing.B) { benchmarkDecode(b, 4096, true) }
@@ -1,8 +1,6 @@
 package bcd
 
 import (
-	// "bytes"
-	// "fmt"
 	"io"
 )
 
@@ -11,23 +9,119 @@ import (
 // (encoded octet may indicate the end of data by using the filler
 // nibble) Writer will not write odd remainder of the encoded input
 // data if any until the next octet is observed.
+//
+// Writer encodes the input in large blocks and buffers encoded data.
+// The data is written to underlying io.Writer when the buffer is
+// full or when Flush is called. Writer implements io.ReaderFrom so
+// io.Copy to Writer avoids intermediate copying.
+//
+// If the filler is leading the alignment of encoded data depends on
+// the total length of the input, so Writer keeps all of the input
+// until Flush is called.
 type Writer struct {
 	*Encoder
 	dst  io.Writer
 	err  error
 	word []byte
+	// encoded octets awaiting writing
+	buf []byte
+	// buffer for ReadFrom
+	scratch []byte
+	// number of input bytes consumed so far
+	pos int
 }
 
 // NewWriter creates new Writer with underlying io.Writer.
 func (enc *Encoder) NewWriter(wr io.Writer) *Writer {
-	return &Writer{enc, wr, nil, make([]byte, 0, 2)}
+	return &Writer{
+		Encoder: enc,
+		dst:     wr,
+		word:    make([]byte, 0, 2),
+		buf:     make([]byte, 0, bufSize)}
 }
 
-// Write implements io.Writer interface.
+// Reset discards any unflushed data and state, and switches the
+// Writer to write to wr. Reset allows to reuse Writer instances.
+func (w *Writer) Reset(wr io.Writer) {
+	w.dst, w.err = wr, nil
+	w.word, w.buf, w.pos = w.word[:0], w.buf[:0], 0
+}
+
+// drain writes all saved octets to underlying io.Writer.
+func (w *Writer) drain() error {
+	if w.err != nil || len(w.buf) == 0 {
+		return w.err
+	}
+
+	m, err := w.dst.Write(w.buf)
+	if err == nil && m < len(w.buf) {
+		err = io.ErrShortWrite
+	}
+
+	w.buf, w.err = w.buf[:copy(w.buf, w.buf[m:])], err
+	return err
+}
+
+// emit saves encoded octet b.
+func (w *Writer) emit(b byte) error {
+	if len(w.buf) == cap(w.buf) {
+		if err := w.drain(); err != nil {
+			return err
+		}
+	}
+	w.buf = append(w.buf, b)
+	return nil
+}
+
+// encode encodes even number of symbols from p in blocks. The offset
+// of p in the input is off. Number of consumed bytes of p and
+// possible error is returned.
+func (w *Writer) encode(p []byte, off int) (n int, err error) {
+	for len(p[n:]) >= 2 {
+		if len(w.buf) == cap(w.buf) {
+			if err = w.drain(); err != nil {
+				return
+			}
+		}
+
+		k := cap(w.buf) - len(w.buf)
+		if x := len(p[n:]) / 2; k > x {
+			k = x
+		}
+
+		m, err := w.Encode(w.buf[len(w.buf):len(w.buf)+k], p[n:n+2*k])
+		w.buf = w.buf[:len(w.buf)+m]
+		if e, ok := err.(*Error); ok {
+			e.Offset += off + n
+			return n + 2*m, e
+		}
+		n += 2 * k
+	}
+	return
+}
+
+// Write implements io.Writer interface. If the input contains
+// unmapped symbols the error is of type *Error.
 func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
 	if len(p) == 0 {
 		return 0, nil
 	}
+	defer func() { w.pos += n }()
+
+	if w.leading {
+		// validate and keep until Flush
+		for n = range p {
+			if w.hash[p[n]] > 0xf {
+				w.word = append(w.word, p[:n]...)
+				return n, w.symbolError(p[n:], w.pos+n)
+			}
+		}
+		w.word = append(w.word, p...)
+		return len(p), nil
+	}
 
 	// if we have remaining byte from previous run
 	// join it with one of new input and encode
@@ -35,9 +129,9 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 		x := append(w.word, p[0])
 		_, b, err := w.pack(x)
 		if err != nil {
-			return 0, err
+			return 0, w.symbolError(x, w.pos-1)
 		}
-		if _, err = w.dst.Write([]byte{b}); err != nil {
+		if err = w.emit(b); err != nil {
 			return 0, err
 		}
 		w.word = w.word[:0]
@@ -45,15 +139,9 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 	}
 
 	// encode even number of bytes
-	for len(p[n:]) >= 2 {
-		_, b, err := w.pack(p[n : n+2])
-		if err != nil {
-			return n, err
-		}
-		if _, err = w.dst.Write([]byte{b}); err != nil {
-			return n, err
-		}
-		n += 2
+	m, err := w.encode(p[n:len(p)-(len(p)-n)%2], w.pos+n)
+	if n += m; err != nil {
+		return n, err
 	}
 
 	// save remainder
@@ -62,32 +150,67 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 		n += 1
 	}
 
-	return
+	return n, nil
 }
 
-// Encodes all backlogged data to underlying Writer.  If number of
-// bytes is odd, the padding fillers will be applied. Because of this
-// the main usage of Flush is right before stopping Write()-ing data
-// to properly finalize the encoding process.
-func (w *Writer) Flush() error {
-	if len(w.word) == 0 {
-		return nil
+// ReadFrom implements io.ReaderFrom interface. It reads the data from
+// r until EOF and encodes it. Please note that ReadFrom does not
+// call Flush.
+func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
+	if w.scratch == nil {
+		w.scratch = make([]byte, DecodedLen(bufSize))
 	}
-	n, b, err := w.pack(w.word)
+
+	for {
+		m, err := r.Read(w.scratch)
+		if m > 0 {
+			m, err := w.Write(w.scratch[:m])
+			if n += int64(m); err != nil {
+				return n, err
+			}
+		}
+
+		if err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Encodes all backlogged data and writes all buffered data to
+// underlying Writer.  If number of bytes is odd, the padding fillers
+// will be applied. Because of this the main usage of Flush is right
+// before stopping Write()-ing data to properly finalize the encoding
+// process.
+func (w *Writer) Flush() error {
+	word := w.word
 	w.word = w.word[:0]
-	if err != nil {
-		// panic("hell")
-		return err
+	off := w.pos - len(word)
+
+	i := 0
+	if len(word)%2 != 0 {
+		// the odd symbol is the first one if the filler is leading,
+		// otherwise it's the only one
+		_, b, err := w.pack(word[:1])
+		if err != nil {
+			return w.symbolError(word, off)
+		}
+		if err = w.emit(b); err != nil {
+			return err
+		}
+		i = 1
 	}
-	if n == 0 {
-		return nil
+
+	if _, err := w.encode(word[i:], off+i); err != nil {
+		return err
 	}
-	_, err = w.dst.Write([]byte{b})
-	return err
+
+	return w.drain()
 }
 
 // Buffered returns the number of bytes stored in backlog awaiting for
-// its pair.
+// its pair or, if the filler is leading, for Flush.
 func (w *Writer) Buffered() int {
 	return len(w.word)
 }
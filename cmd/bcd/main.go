@@ -0,0 +1,287 @@
+/*
+Command bcd encodes decimal strings into BCD and decodes BCD data
+back using package bcd.
+
+Usage:
+
+	bcd [flags] [input ...]
+
+Each argument is processed separately and the result is printed on a
+separate line. If no arguments are given the input is read from stdin:
+line by line if the input is textual or as a whole if -format=bin is
+used for decoding.
+
+Encoded data is read and printed in hex by default. Spaces, colons and
+dashes in hex input are ignored, so dumps copied from Wireshark can be
+used as is. For example:
+
+	$ bcd 79001234567
+	9700214365f7
+	$ bcd -d 97:00:21:43:65:f7
+	79001234567
+	$ bcd -d -t standard -lossy 12a4f5
+	12?4_5
+	offset 1: unmapped nibble in high half of 0xa4
+	offset 2: unexpected filler in high half of 0xf5
+	bcd: 2 invalid nibbles found
+
+With -lossy invalid nibbles are rendered as '?' and unexpected fillers
+as '_', and the exit status is non-zero if any were found.
+
+The table is chosen by the registered name, see bcd.Lookup, or
+specified in the text form as in bcd.ParseBCD. Nibble swap, filler and
+filler position of the table may be overridden with flags.
+*/
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yerden/go-util/bcd"
+)
+
+// options of the command.
+type options struct {
+	decode  bool
+	table   string
+	format  string
+	swap    bool
+	filler  string
+	leading bool
+	ignore  bool
+	lossy   bool
+
+	// flags set explicitly
+	set map[string]bool
+}
+
+func newFlagSet(opts *options, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("bcd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: bcd [flags] [input ...]\n")
+		fs.PrintDefaults()
+	}
+
+	fs.BoolVar(&opts.decode, "d", false, "Decode BCD data instead of encoding")
+	fs.StringVar(&opts.table, "t", "telephony", "BCD table name or text form")
+	fs.StringVar(&opts.format, "format", "hex", "Format of encoded data: hex or bin")
+	fs.BoolVar(&opts.swap, "swap", false, "Swap nibbles, overrides the table")
+	fs.StringVar(&opts.filler, "filler", "", "Filler nibble in hex, overrides the table")
+	fs.BoolVar(&opts.leading, "leading", false, "Filler is leading, overrides the table")
+	fs.BoolVar(&opts.ignore, "ignore-filler", false, "Ignore filler in the middle of data")
+	fs.BoolVar(&opts.lossy, "lossy", false, "Decode invalid data printing diagnostics to stderr")
+	return fs
+}
+
+func (opts *options) config() (*bcd.BCD, error) {
+	c, ok := bcd.Lookup(opts.table)
+	if !ok {
+		var err error
+		if c, err = bcd.ParseBCD(opts.table); err != nil {
+			return nil, err
+		}
+	}
+
+	config := *c
+	if opts.set["swap"] {
+		config.SwapNibbles = opts.swap
+	}
+	if opts.set["leading"] {
+		config.LeadingFiller = opts.leading
+	}
+	if opts.set["filler"] {
+		x, err := strconv.ParseUint(opts.filler, 16, 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filler %q", opts.filler)
+		}
+		config.Filler = byte(x)
+	}
+	return &config, nil
+}
+
+// unhex decodes hex string ignoring separators.
+func unhex(s []byte) ([]byte, error) {
+	s = bytes.Map(func(r rune) rune {
+		if strings.ContainsRune(" \t\r\n:-", r) {
+			return -1
+		}
+		return r
+	}, s)
+	return hex.DecodeString(string(s))
+}
+
+type processor struct {
+	*options
+	codec   *bcd.Codec
+	nibble  byte // valid nibble of the table
+	out     *bufio.Writer
+	stderr  io.Writer
+	invalid int
+}
+
+func (p *processor) encodeInput(in []byte) error {
+	out, err := p.codec.AppendEncode(nil, bytes.TrimSpace(in))
+	if err != nil {
+		return err
+	}
+
+	if p.format == "bin" {
+		_, err = p.out.Write(out)
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, hex.EncodeToString(out))
+	return err
+}
+
+func (p *processor) decodeInput(in []byte) (err error) {
+	if p.format != "bin" {
+		if in, err = unhex(in); err != nil {
+			return err
+		}
+	}
+
+	out := make([]byte, bcd.DecodedLen(len(in)))
+	if !p.lossy {
+		n, err := p.codec.Decode(out, in)
+		if err != nil {
+			return err
+		}
+		out = out[:n]
+	} else {
+		n, _ := p.codec.DecodeLossy(out, in)
+		out = out[:n]
+		errs := p.invalidNibbles(in)
+		defer func() {
+			p.out.Flush()
+			for _, e := range errs {
+				half := "low"
+				if e.High {
+					half = "high"
+				}
+				fmt.Fprintf(p.stderr, "offset %d: %v in %s half of 0x%02x\n",
+					e.Offset, e.Reason, half, in[e.Offset])
+			}
+			p.invalid += len(errs)
+		}()
+	}
+
+	_, err = fmt.Fprintln(p.out, string(out))
+	return err
+}
+
+// invalidNibbles returns the errors of all invalid nibbles in src.
+// Decoder reports the first invalid nibble only, so it is replaced
+// with the valid one and decoding is repeated.
+func (p *processor) invalidNibbles(src []byte) (errs []*bcd.Error) {
+	src = append([]byte(nil), src...)
+	dst := make([]byte, bcd.DecodedLen(len(src)))
+	for {
+		_, err := p.codec.Decode(dst, src)
+		e, ok := err.(*bcd.Error)
+		if !ok {
+			return errs
+		}
+		errs = append(errs, e)
+
+		if e.High {
+			src[e.Offset] = p.nibble<<4 | src[e.Offset]&0x0f
+		} else {
+			src[e.Offset] = src[e.Offset]&0xf0 | p.nibble
+		}
+	}
+}
+
+func (p *processor) process(in []byte) error {
+	if p.decode {
+		return p.decodeInput(in)
+	}
+	return p.encodeInput(in)
+}
+
+func (p *processor) run(args []string, stdin io.Reader) error {
+	config, err := p.config()
+	if err != nil {
+		return err
+	}
+
+	if p.format != "hex" && p.format != "bin" {
+		return fmt.Errorf("invalid format %q", p.format)
+	}
+
+	p.codec = bcd.NewCodec(config)
+	p.codec.IgnoreFiller = p.ignore
+	for _, nib := range config.Map {
+		p.nibble = nib
+		break
+	}
+	defer p.out.Flush()
+
+	for _, arg := range args {
+		if err := p.process([]byte(arg)); err != nil {
+			return err
+		}
+	}
+
+	if len(args) == 0 && p.decode && p.format == "bin" {
+		in, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		if err := p.process(in); err != nil {
+			return err
+		}
+	} else if len(args) == 0 {
+		s := bufio.NewScanner(stdin)
+		for s.Scan() {
+			if err := p.process(s.Bytes()); err != nil {
+				return err
+			}
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+
+	if p.invalid > 0 {
+		return fmt.Errorf("%d invalid nibbles found", p.invalid)
+	}
+	return nil
+}
+
+// run executes the command with command line arguments args and
+// returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := &options{set: make(map[string]bool)}
+	fs := newFlagSet(opts, stderr)
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+	fs.Visit(func(f *flag.Flag) { opts.set[f.Name] = true })
+
+	p := &processor{
+		options: opts,
+		out:     bufio.NewWriter(stdout),
+		stderr:  stderr}
+	if err := p.run(fs.Args(), stdin); err != nil {
+		p.out.Flush()
+		fmt.Fprintln(stderr, "bcd:", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
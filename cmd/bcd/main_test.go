@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, c := range []struct {
+		args   []string
+		stdin  string
+		stdout string
+		stderr string
+		code   int
+	}{
+		{args: []string{"79001234567"}, stdout: "9700214365f7\n"},
+		{args: []string{"-d", "97:00:21:43:65:f7"}, stdout: "79001234567\n"},
+		{args: []string{"-d", "97 00-21"}, stdout: "790012\n"},
+		{args: []string{"12", "345"}, stdout: "21\n43f5\n"},
+		{args: []string{"-t", "standard", "-leading", "123"}, stdout: "f123\n"},
+		{args: []string{"-t", "standard", "-filler", "a", "123"}, stdout: "123a\n"},
+		{args: []string{"-swap", "-t", "standard", "12"}, stdout: "21\n"},
+		{stdin: "123\n45\n", stdout: "21f3\n54\n"},
+		{args: []string{"-d"}, stdin: "21f3\n", stdout: "123\n"},
+		{args: []string{"-d", "-format", "bin"}, stdin: "\x21\xf3", stdout: "123\n"},
+		{args: []string{"-format", "bin", "123"}, stdout: "\x21\xf3"},
+		{
+			args:   []string{"-d", "-t", "standard", "-lossy", "12a4f5"},
+			stdout: "12?4_5\n",
+			stderr: "offset 1: unmapped nibble in high half of 0xa4\n" +
+				"offset 2: unexpected filler in high half of 0xf5\n" +
+				"bcd: 2 invalid nibbles found\n",
+			code: 1,
+		},
+		{
+			args:   []string{"-d", "-t", "standard", "-lossy", "-leading", "ffab1f"},
+			stdout: "_??1_\n",
+			stderr: "offset 0: unexpected filler in low half of 0xff\n" +
+				"offset 1: unmapped nibble in high half of 0xab\n" +
+				"offset 1: unmapped nibble in low half of 0xab\n" +
+				"offset 2: unexpected filler in low half of 0x1f\n" +
+				"bcd: 4 invalid nibbles found\n",
+			code: 1,
+		},
+		{args: []string{"-d", "-lossy", "2143"}, stdout: "1234\n"},
+		{args: []string{"-d", "2f43"}, stderr: "bcd: Bad BCD data: unexpected filler in low half of 0x2f at offset 0\n", code: 1},
+		{args: []string{"12x"}, stderr: "bcd: non-encodable data: unmapped symbol 'x' at offset 2\n", code: 1},
+		{args: []string{"-d", "zz"}, code: 1},
+		{args: []string{"-t", "nonexistent", "1"}, code: 1},
+		{args: []string{"-filler", "x", "1"}, code: 1},
+		{args: []string{"-format", "oct", "1"}, code: 1},
+		{args: []string{"-bogus"}, code: 2},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, strings.NewReader(c.stdin), &stdout, &stderr)
+		if code != c.code {
+			t.Errorf("%q: exit code %d, want %d, stderr %q", c.args, code, c.code, stderr.String())
+		}
+		if stdout.String() != c.stdout {
+			t.Errorf("%q: stdout %q, want %q", c.args, stdout.String(), c.stdout)
+		}
+		if c.stderr != "" && stderr.String() != c.stderr {
+			t.Errorf("%q: stderr %q, want %q", c.args, stderr.String(), c.stderr)
+		}
+		if c.code != 0 && stderr.Len() == 0 {
+			t.Errorf("%q: stderr is empty", c.args)
+		}
+	}
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type kind int
+
+const (
+	kindInt kind = iota
+	kindBool
+	kindFloat
+	kindString
+	kindSlice
+	kindArray
+	kindNamed
+)
+
+// typeInfo describes Go type of the field.
+type typeInfo struct {
+	kind
+	// Go type expression
+	name string
+	// natural size of integers and floats
+	size   int
+	signed bool
+	// element type of slices and arrays
+	elem *typeInfo
+	// length of arrays
+	len string
+}
+
+// isByte tells if t is a byte.
+func (t *typeInfo) isByte() bool {
+	return t.kind == kindInt && t.size == 1 && !t.signed
+}
+
+// fieldInfo describes the struct field.
+type fieldInfo struct {
+	name  string
+	typ   *typeInfo
+	order string
+	// wire size of integers
+	size     int
+	lenField string
+	prefix   int
+	pad      int
+	// the name of the slice which length this field holds
+	lenOf string
+}
+
+// basicTypes lists the supported basic types. As in field.Marshal the
+// size of int, uint and uintptr does not depend on the platform.
+var basicTypes = map[string]typeInfo{
+	"bool":    {kind: kindBool, size: 1},
+	"byte":    {kind: kindInt, size: 1},
+	"uint8":   {kind: kindInt, size: 1},
+	"uint16":  {kind: kindInt, size: 2},
+	"uint32":  {kind: kindInt, size: 4},
+	"uint64":  {kind: kindInt, size: 8},
+	"uint":    {kind: kindInt, size: 8},
+	"uintptr": {kind: kindInt, size: 8},
+	"int8":    {kind: kindInt, size: 1, signed: true},
+	"int16":   {kind: kindInt, size: 2, signed: true},
+	"int32":   {kind: kindInt, size: 4, signed: true},
+	"int64":   {kind: kindInt, size: 8, signed: true},
+	"int":     {kind: kindInt, size: 8, signed: true},
+	"float32": {kind: kindFloat, size: 4},
+	"float64": {kind: kindFloat, size: 8},
+	"string":  {kind: kindString},
+}
+
+func exprString(fset *token.FileSet, e ast.Expr) string {
+	var b bytes.Buffer
+	format.Node(&b, fset, e)
+	return b.String()
+}
+
+// typeOf returns the description of type expression e.
+func typeOf(fset *token.FileSet, e ast.Expr) (*typeInfo, error) {
+	name := exprString(fset, e)
+	switch e := e.(type) {
+	case *ast.Ident:
+		if t, ok := basicTypes[name]; ok {
+			t.name = name
+			return &t, nil
+		}
+		return &typeInfo{kind: kindNamed, name: name}, nil
+	case *ast.SelectorExpr:
+		return &typeInfo{kind: kindNamed, name: name}, nil
+	case *ast.ArrayType:
+		elem, err := typeOf(fset, e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if elem.kind == kindSlice || elem.kind == kindArray || elem.kind == kindString {
+			return nil, fmt.Errorf("unsupported element type of %s", name)
+		}
+
+		t := &typeInfo{kind: kindSlice, name: name, elem: elem}
+		if e.Len != nil {
+			t.kind, t.len = kindArray, exprString(fset, e.Len)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", name)
+}
+
+var intSizes = map[string]int{"u8": 1, "u16": 2, "u24": 3, "u32": 4, "u64": 8}
+
+// parseTag parses the field tag.
+func parseTag(f *fieldInfo, tag string) error {
+	f.order = "BigEndian"
+	for _, s := range strings.Split(tag, ",") {
+		key, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			key, value = s[:i], s[i+1:]
+		}
+
+		var err error
+		switch key {
+		case "":
+		case "be":
+			f.order = "BigEndian"
+		case "le":
+			f.order = "LittleEndian"
+		case "u8", "u16", "u24", "u32", "u64":
+			f.size = intSizes[key]
+		case "len":
+			f.lenField = value
+		case "prefix":
+			if f.prefix = intSizes[value]; f.prefix == 0 || f.prefix > 4 {
+				err = fmt.Errorf("invalid prefix")
+			}
+		case "pad":
+			if f.pad, err = strconv.Atoi(value); err == nil && f.pad <= 0 {
+				err = fmt.Errorf("invalid padding")
+			}
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+
+		if err != nil {
+			return fmt.Errorf("tag %q: %v", s, err)
+		}
+	}
+	return nil
+}
+
+// structFields returns the fields of struct type.
+func structFields(fset *token.FileSet, st *ast.StructType) ([]*fieldInfo, error) {
+	var fields []*fieldInfo
+	index := make(map[string]*fieldInfo)
+	for _, field := range st.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			s, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(s).Get("field")
+		}
+		if tag == "-" {
+			continue
+		}
+
+		t, err := typeOf(fset, field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			// embedded field
+			name := t.name[strings.LastIndexByte(t.name, '.')+1:]
+			names = []*ast.Ident{ast.NewIdent(strings.TrimPrefix(name, "*"))}
+		}
+
+		for _, name := range names {
+			f := &fieldInfo{name: name.Name, typ: t}
+			if err := parseTag(f, tag); err != nil {
+				return nil, fmt.Errorf("field %s: %v", f.name, err)
+			}
+
+			if f.size != 0 && t.kind != kindInt && (t.elem == nil || t.elem.kind != kindInt) {
+				return nil, fmt.Errorf("field %s: size of non-integer", f.name)
+			}
+
+			if f.name == "_" && (t.kind != kindArray || !t.elem.isByte()) {
+				return nil, fmt.Errorf("blank field should be byte array")
+			}
+
+			if f.lenField != "" {
+				lf, ok := index[f.lenField]
+				if !ok || lf.typ.kind != kindInt {
+					return nil, fmt.Errorf("field %s: length field %s should be preceding integer", f.name, f.lenField)
+				}
+				lf.lenOf = f.name
+			}
+
+			if (f.lenField != "" || f.prefix != 0) && t.kind != kindSlice && t.kind != kindString {
+				return nil, fmt.Errorf("field %s: length of non-slice", f.name)
+			}
+
+			index[f.name] = f
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+// generator accumulates the generated code.
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// uintType returns unsigned integer type and the suffix of field
+// functions for the size.
+func uintType(size int) (string, string) {
+	switch size {
+	case 1:
+		return "uint8", "Uint8"
+	case 2:
+		return "uint16", "Uint16"
+	case 3:
+		return "uint32", "Uint24"
+	case 4:
+		return "uint32", "Uint32"
+	}
+	return "uint64", "Uint64"
+}
+
+// writeFunc returns the field function which writes or reads integer
+// of the size.
+func writeFunc(op, order string, size int) string {
+	_, suffix := uintType(size)
+	if size == 1 {
+		return "field." + op + suffix
+	}
+	return "field." + order + "." + op + suffix
+}
+
+// wireSize returns the size of scalar value of type t in the field.
+func wireSize(f *fieldInfo, t *typeInfo) int {
+	if t.kind == kindInt && f.size != 0 {
+		return f.size
+	}
+	return t.size
+}
+
+// lenExpr returns the expression of encoded length of scalar value
+// x of type t.
+func lenExpr(f *fieldInfo, t *typeInfo, x string) string {
+	if t.kind == kindNamed {
+		return x + ".EncodedLen()"
+	}
+	return strconv.Itoa(wireSize(f, t))
+}
+
+// append generates the code which appends scalar value x of type t.
+func (g *generator) append(f *fieldInfo, t *typeInfo, x string) {
+	size := wireSize(f, t)
+	utype, _ := uintType(size)
+	fn := writeFunc("Write", f.order, size)
+	switch t.kind {
+	case kindInt:
+		if t.name != utype {
+			x = fmt.Sprintf("%s(%s)", utype, x)
+		}
+		g.printf("data = %s(data, %s)\n", fn, x)
+	case kindBool:
+		g.printf("if %s {\ndata = append(data, 1)\n} else {\ndata = append(data, 0)\n}\n", x)
+	case kindFloat:
+		g.imports["math"] = true
+		g.printf("data = %s(data, math.Float%dbits(%s))\n", fn, 8*size, x)
+	case kindNamed:
+		g.printf("data = %s.AppendTo(data)\n", x)
+	}
+}
+
+// fail is the statement returning on decoding error.
+const fail = "return nil, false"
+
+// prune generates the code which decodes scalar value into x of
+// type t. If scoped is true the code is already enclosed in a block.
+func (g *generator) prune(f *fieldInfo, t *typeInfo, x string, scoped bool) {
+	size := wireSize(f, t)
+	utype, _ := uintType(size)
+	fn := writeFunc("Read", f.order, size)
+
+	if t.kind == kindNamed {
+		g.printf("if data, ok = %s.PruneFrom(data); !ok {\n%s\n}\n", x, fail)
+		return
+	}
+
+	if t.kind == kindInt && !t.signed && (t.name == utype || t.name == "byte" && utype == "uint8") {
+		// read directly into the field
+		g.printf("if data, ok = %s(data, &%s); !ok {\n%s\n}\n", fn, x, fail)
+		return
+	}
+
+	if !scoped {
+		g.printf("{\n")
+		defer g.printf("}\n")
+	}
+	g.printf("var x %s\n", utype)
+	g.printf("if data, ok = %s(data, &x); !ok {\n%s\n}\n", fn, fail)
+	switch t.kind {
+	case kindInt:
+		bits := 8 * size
+		if size == 3 {
+			bits = 32
+		}
+		if shift := bits - 8*size; t.signed && shift > 0 {
+			// sign extension
+			g.printf("%s = %s(int%d(x<<%d) >> %d)\n", x, t.name, bits, shift, shift)
+		} else if t.name == fmt.Sprintf("int%d", bits) {
+			g.printf("%s = int%d(x)\n", x, bits)
+		} else if t.signed {
+			g.printf("%s = %s(int%d(x))\n", x, t.name, bits)
+		} else {
+			g.printf("%s = %s(x)\n", x, t.name)
+		}
+	case kindBool:
+		g.printf("%s = x != 0\n", x)
+	case kindFloat:
+		g.imports["math"] = true
+		g.printf("%s = math.Float%dfrombits(x)\n", x, 8*size)
+	}
+}
+
+// genEncodedLen generates EncodedLen method.
+func (g *generator) genEncodedLen(name string, fields []*fieldInfo) {
+	g.printf("// EncodedLen returns the length of encoded %s.\n", name)
+	g.printf("func (m *%s) EncodedLen() int {\nn := 0\n", name)
+	for _, f := range fields {
+		x := "m." + f.name
+		t := f.typ
+		g.printf("// %s\n", f.name)
+
+		// padded field length is accumulated separately
+		n := "n"
+		if f.pad != 0 {
+			n = "k"
+			g.printf("{\nk := 0\n")
+		}
+
+		if f.prefix != 0 {
+			g.printf("%s += %d\n", n, f.prefix)
+		}
+
+		switch {
+		case f.name == "_":
+			g.printf("%s += %s\n", n, t.len)
+		case t.kind == kindString || t.elem != nil && t.elem.isByte():
+			g.printf("%s += len(%s)\n", n, x)
+		case t.elem != nil && t.elem.kind == kindNamed:
+			g.printf("for i := range %s {\n%s += %s\n}\n", x, n, lenExpr(f, t.elem, x+"[i]"))
+		case t.elem != nil:
+			g.printf("%s += len(%s) * %s\n", n, x, lenExpr(f, t.elem, ""))
+		default:
+			g.printf("%s += %s\n", n, lenExpr(f, t, x))
+		}
+
+		if f.pad != 0 {
+			g.printf("n += k + (%d-k%%%d)%%%d\n}\n", f.pad, f.pad, f.pad)
+		}
+	}
+	g.printf("return n\n}\n\n")
+}
+
+// genAppendTo generates AppendTo method.
+func (g *generator) genAppendTo(name string, fields []*fieldInfo) {
+	g.printf("// AppendTo implements field.Serializable interface.\n")
+	g.printf("func (m *%s) AppendTo(data []byte) []byte {\n", name)
+	for _, f := range fields {
+		x := "m." + f.name
+		t := f.typ
+		g.printf("// %s\n", f.name)
+		if f.pad != 0 {
+			g.printf("start%s := len(data)\n", f.name)
+		}
+
+		if f.prefix != 0 {
+			utype, _ := uintType(f.prefix)
+			g.printf("data = %s(data, %s(len(%s)))\n", writeFunc("Write", f.order, f.prefix), utype, x)
+		}
+
+		switch {
+		case f.name == "_":
+			g.printf("data = append(data, make([]byte, %s)...)\n", t.len)
+		case f.lenOf != "":
+			lt := *t
+			lt.name = "int"
+			g.append(f, &lt, fmt.Sprintf("len(m.%s)", f.lenOf))
+		case t.kind == kindString || t.kind == kindSlice && t.elem.isByte():
+			g.printf("data = append(data, %s...)\n", x)
+		case t.kind == kindArray && t.elem.isByte():
+			g.printf("data = append(data, %s[:]...)\n", x)
+		case t.kind == kindSlice || t.kind == kindArray:
+			g.printf("for i := range %s {\n", x)
+			g.append(f, t.elem, x+"[i]")
+			g.printf("}\n")
+		default:
+			g.append(f, t, x)
+		}
+
+		if f.pad != 0 {
+			g.printf("for (len(data)-start%s)%%%d != 0 {\ndata = append(data, 0)\n}\n", f.name, f.pad)
+		}
+	}
+	g.printf("return data\n}\n\n")
+}
+
+// genPruneFrom generates PruneFrom method.
+func (g *generator) genPruneFrom(name string, fields []*fieldInfo) {
+	g.printf("// PruneFrom implements field.Serializable interface.\n")
+	g.printf("func (m *%s) PruneFrom(data []byte) ([]byte, bool) {\n", name)
+	if len(fields) > 0 {
+		g.printf("var ok bool\n")
+	}
+	for _, f := range fields {
+		x := "m." + f.name
+		t := f.typ
+		g.printf("// %s\n", f.name)
+		if f.pad != 0 {
+			g.printf("start%s := len(data)\n", f.name)
+		}
+
+		// number of elements of slice or string
+		n := ""
+		if f.lenField != "" {
+			n = fmt.Sprintf("int(m.%s)", f.lenField)
+		} else if f.prefix != 0 {
+			utype, _ := uintType(f.prefix)
+			g.printf("var n%s %s\n", f.name, utype)
+			g.printf("if data, ok = %s(data, &n%s); !ok {\n%s\n}\n", writeFunc("Read", f.order, f.prefix), f.name, fail)
+			n = fmt.Sprintf("int(n%s)", f.name)
+		}
+
+		switch {
+		case f.name == "_":
+			g.printf("if data, ok = field.SkipBytes(data, %s); !ok {\n%s\n}\n", t.len, fail)
+		case t.kind == kindArray && t.elem.isByte():
+			g.printf("if data, ok = field.CopyBytes(data, %s[:]); !ok {\n%s\n}\n", x, fail)
+		case t.kind == kindArray:
+			g.printf("for i := range %s {\n", x)
+			g.prune(f, t.elem, x+"[i]", true)
+			g.printf("}\n")
+		case t.kind == kindString || t.kind == kindSlice && t.elem.isByte():
+			if n == "" {
+				n = "len(data)"
+			}
+			if t.kind == kindString {
+				g.printf("{\nvar b []byte\nif data, ok = field.ReadBytes(data, &b, %s); !ok {\n%s\n}\n%s = string(b)\n}\n", n, fail, x)
+			} else {
+				g.printf("if data, ok = field.ReadBytes(data, &%s, %s); !ok {\n%s\n}\n", x, n, fail)
+			}
+		case t.kind == kindSlice && n == "":
+			g.printf("%s = %s{}\n", x, t.name)
+			g.printf("for len(data) > 0 {\nvar e %s\nk := len(data)\n", t.elem.name)
+			g.prune(f, t.elem, "e", true)
+			// element consuming no data would loop forever
+			g.printf("if len(data) == k {\n%s\n}\n", fail)
+			g.printf("%s = append(%s, e)\n}\n", x, x)
+		case t.kind == kindSlice:
+			g.printf("if %s > len(data) {\n%s\n}\n", n, fail)
+			g.printf("%s = make(%s, %s)\n", x, t.name, n)
+			g.printf("for i := range %s {\n", x)
+			g.prune(f, t.elem, x+"[i]", true)
+			g.printf("}\n")
+		default:
+			g.prune(f, t, x, false)
+		}
+
+		if f.pad != 0 {
+			g.printf("if k := (start%s - len(data)) %% %d; k != 0 {\n", f.name, f.pad)
+			g.printf("if data, ok = field.SkipBytes(data, %d-k); !ok {\n%s\n}\n}\n", f.pad, fail)
+		}
+	}
+	g.printf("return data, true\n}\n\n")
+}
+
+// generate generates the methods for the types declared in the
+// package in dir and returns formatted source code.
+func generate(dir string, types []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s: expected one package, found %d", dir, len(pkgs))
+	}
+
+	structs := make(map[string]*ast.StructType)
+	var pkgName string
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if ts, ok := n.(*ast.TypeSpec); ok {
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						structs[ts.Name.Name] = st
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	g := &generator{imports: map[string]bool{"github.com/yerden/go-util/field": true}}
+	for _, name := range types {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+
+		fields, err := structFields(fset, st)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %v", name, err)
+		}
+
+		g.printf("var _ field.Serializable = (*%s)(nil)\n\n", name)
+		g.genEncodedLen(name, fields)
+		g.genAppendTo(name, fields)
+		g.genPruneFrom(name, fields)
+	}
+
+	// standard packages go first
+	var std, ext []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			ext = append(ext, strconv.Quote(imp))
+		} else {
+			std = append(std, strconv.Quote(imp))
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(ext)
+	imports := strings.Join(std, "\n")
+	if len(std) > 0 {
+		imports += "\n\n"
+	}
+	imports += strings.Join(ext, "\n")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"fieldgen -type %s\"; DO NOT EDIT.\n\n", strings.Join(types, ","))
+	fmt.Fprintf(&b, "package %s\n\nimport (\n%s\n)\n\n", pkgName, imports)
+	b.Write(g.buf.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %v", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,414 @@
+// Code generated by "fieldgen -type Header,Point,Message,Trailer,Empty,Empties"; DO NOT EDIT.
+
+package golden
+
+import (
+	"math"
+
+	"github.com/yerden/go-util/field"
+)
+
+var _ field.Serializable = (*Header)(nil)
+
+// EncodedLen returns the length of encoded Header.
+func (m *Header) EncodedLen() int {
+	n := 0
+	// Version
+	n += 1
+	// Flags
+	n += 2
+	// Length
+	n += 3
+	// Offset
+	n += 2
+	// _
+	n += 2
+	// Magic
+	n += len(m.Magic)
+	// Ready
+	n += 1
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Header) AppendTo(data []byte) []byte {
+	// Version
+	data = field.WriteUint8(data, m.Version)
+	// Flags
+	data = field.LittleEndian.WriteUint16(data, m.Flags)
+	// Length
+	data = field.BigEndian.WriteUint24(data, uint32(m.Length))
+	// Offset
+	data = field.BigEndian.WriteUint16(data, uint16(m.Offset))
+	// _
+	data = append(data, make([]byte, 2)...)
+	// Magic
+	data = append(data, m.Magic[:]...)
+	// Ready
+	if m.Ready {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Header) PruneFrom(data []byte) ([]byte, bool) {
+	var ok bool
+	// Version
+	if data, ok = field.ReadUint8(data, &m.Version); !ok {
+		return nil, false
+	}
+	// Flags
+	if data, ok = field.LittleEndian.ReadUint16(data, &m.Flags); !ok {
+		return nil, false
+	}
+	// Length
+	{
+		var x uint32
+		if data, ok = field.BigEndian.ReadUint24(data, &x); !ok {
+			return nil, false
+		}
+		m.Length = int(int32(x<<8) >> 8)
+	}
+	// Offset
+	{
+		var x uint16
+		if data, ok = field.BigEndian.ReadUint16(data, &x); !ok {
+			return nil, false
+		}
+		m.Offset = int16(x)
+	}
+	// _
+	if data, ok = field.SkipBytes(data, 2); !ok {
+		return nil, false
+	}
+	// Magic
+	if data, ok = field.CopyBytes(data, m.Magic[:]); !ok {
+		return nil, false
+	}
+	// Ready
+	{
+		var x uint8
+		if data, ok = field.ReadUint8(data, &x); !ok {
+			return nil, false
+		}
+		m.Ready = x != 0
+	}
+	return data, true
+}
+
+var _ field.Serializable = (*Point)(nil)
+
+// EncodedLen returns the length of encoded Point.
+func (m *Point) EncodedLen() int {
+	n := 0
+	// X
+	n += 4
+	// Y
+	n += 4
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Point) AppendTo(data []byte) []byte {
+	// X
+	data = field.BigEndian.WriteUint32(data, math.Float32bits(m.X))
+	// Y
+	data = field.BigEndian.WriteUint32(data, math.Float32bits(m.Y))
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Point) PruneFrom(data []byte) ([]byte, bool) {
+	var ok bool
+	// X
+	{
+		var x uint32
+		if data, ok = field.BigEndian.ReadUint32(data, &x); !ok {
+			return nil, false
+		}
+		m.X = math.Float32frombits(x)
+	}
+	// Y
+	{
+		var x uint32
+		if data, ok = field.BigEndian.ReadUint32(data, &x); !ok {
+			return nil, false
+		}
+		m.Y = math.Float32frombits(x)
+	}
+	return data, true
+}
+
+var _ field.Serializable = (*Message)(nil)
+
+// EncodedLen returns the length of encoded Message.
+func (m *Message) EncodedLen() int {
+	n := 0
+	// Header
+	n += m.Header.EncodedLen()
+	// Count
+	n += 1
+	// Points
+	for i := range m.Points {
+		n += m.Points[i].EncodedLen()
+	}
+	// Name
+	{
+		k := 0
+		k += 1
+		k += len(m.Name)
+		n += k + (4-k%4)%4
+	}
+	// Values
+	n += 2
+	n += len(m.Values) * 4
+	// Codes
+	n += len(m.Codes) * 2
+	// Delta
+	n += 1
+	// Payload
+	n += len(m.Payload)
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Message) AppendTo(data []byte) []byte {
+	// Header
+	data = m.Header.AppendTo(data)
+	// Count
+	data = field.WriteUint8(data, uint8(len(m.Points)))
+	// Points
+	for i := range m.Points {
+		data = m.Points[i].AppendTo(data)
+	}
+	// Name
+	startName := len(data)
+	data = field.WriteUint8(data, uint8(len(m.Name)))
+	data = append(data, m.Name...)
+	for (len(data)-startName)%4 != 0 {
+		data = append(data, 0)
+	}
+	// Values
+	data = field.LittleEndian.WriteUint16(data, uint16(len(m.Values)))
+	for i := range m.Values {
+		data = field.LittleEndian.WriteUint32(data, uint32(m.Values[i]))
+	}
+	// Codes
+	for i := range m.Codes {
+		data = field.BigEndian.WriteUint16(data, m.Codes[i])
+	}
+	// Delta
+	data = field.WriteUint8(data, uint8(m.Delta))
+	// Payload
+	data = append(data, m.Payload...)
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Message) PruneFrom(data []byte) ([]byte, bool) {
+	var ok bool
+	// Header
+	if data, ok = m.Header.PruneFrom(data); !ok {
+		return nil, false
+	}
+	// Count
+	if data, ok = field.ReadUint8(data, &m.Count); !ok {
+		return nil, false
+	}
+	// Points
+	if int(m.Count) > len(data) {
+		return nil, false
+	}
+	m.Points = make([]Point, int(m.Count))
+	for i := range m.Points {
+		if data, ok = m.Points[i].PruneFrom(data); !ok {
+			return nil, false
+		}
+	}
+	// Name
+	startName := len(data)
+	var nName uint8
+	if data, ok = field.ReadUint8(data, &nName); !ok {
+		return nil, false
+	}
+	{
+		var b []byte
+		if data, ok = field.ReadBytes(data, &b, int(nName)); !ok {
+			return nil, false
+		}
+		m.Name = string(b)
+	}
+	if k := (startName - len(data)) % 4; k != 0 {
+		if data, ok = field.SkipBytes(data, 4-k); !ok {
+			return nil, false
+		}
+	}
+	// Values
+	var nValues uint16
+	if data, ok = field.LittleEndian.ReadUint16(data, &nValues); !ok {
+		return nil, false
+	}
+	if int(nValues) > len(data) {
+		return nil, false
+	}
+	m.Values = make([]int32, int(nValues))
+	for i := range m.Values {
+		var x uint32
+		if data, ok = field.LittleEndian.ReadUint32(data, &x); !ok {
+			return nil, false
+		}
+		m.Values[i] = int32(x)
+	}
+	// Codes
+	for i := range m.Codes {
+		if data, ok = field.BigEndian.ReadUint16(data, &m.Codes[i]); !ok {
+			return nil, false
+		}
+	}
+	// Delta
+	{
+		var x uint8
+		if data, ok = field.ReadUint8(data, &x); !ok {
+			return nil, false
+		}
+		m.Delta = int8(x)
+	}
+	// Payload
+	if data, ok = field.ReadBytes(data, &m.Payload, len(data)); !ok {
+		return nil, false
+	}
+	return data, true
+}
+
+var _ field.Serializable = (*Trailer)(nil)
+
+// EncodedLen returns the length of encoded Trailer.
+func (m *Trailer) EncodedLen() int {
+	n := 0
+	// Kind
+	n += 1
+	// Seq
+	n += 8
+	// Mark
+	n += 8
+	// Points
+	for i := range m.Points {
+		n += m.Points[i].EncodedLen()
+	}
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Trailer) AppendTo(data []byte) []byte {
+	// Kind
+	data = field.WriteUint8(data, m.Kind)
+	// Seq
+	data = field.BigEndian.WriteUint64(data, uint64(m.Seq))
+	// Mark
+	data = field.BigEndian.WriteUint64(data, uint64(m.Mark))
+	// Points
+	for i := range m.Points {
+		data = m.Points[i].AppendTo(data)
+	}
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Trailer) PruneFrom(data []byte) ([]byte, bool) {
+	var ok bool
+	// Kind
+	if data, ok = field.ReadUint8(data, &m.Kind); !ok {
+		return nil, false
+	}
+	// Seq
+	{
+		var x uint64
+		if data, ok = field.BigEndian.ReadUint64(data, &x); !ok {
+			return nil, false
+		}
+		m.Seq = int(int64(x))
+	}
+	// Mark
+	{
+		var x uint64
+		if data, ok = field.BigEndian.ReadUint64(data, &x); !ok {
+			return nil, false
+		}
+		m.Mark = uintptr(x)
+	}
+	// Points
+	m.Points = []Point{}
+	for len(data) > 0 {
+		var e Point
+		k := len(data)
+		if data, ok = e.PruneFrom(data); !ok {
+			return nil, false
+		}
+		if len(data) == k {
+			return nil, false
+		}
+		m.Points = append(m.Points, e)
+	}
+	return data, true
+}
+
+var _ field.Serializable = (*Empty)(nil)
+
+// EncodedLen returns the length of encoded Empty.
+func (m *Empty) EncodedLen() int {
+	n := 0
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Empty) AppendTo(data []byte) []byte {
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Empty) PruneFrom(data []byte) ([]byte, bool) {
+	return data, true
+}
+
+var _ field.Serializable = (*Empties)(nil)
+
+// EncodedLen returns the length of encoded Empties.
+func (m *Empties) EncodedLen() int {
+	n := 0
+	// List
+	for i := range m.List {
+		n += m.List[i].EncodedLen()
+	}
+	return n
+}
+
+// AppendTo implements field.Serializable interface.
+func (m *Empties) AppendTo(data []byte) []byte {
+	// List
+	for i := range m.List {
+		data = m.List[i].AppendTo(data)
+	}
+	return data
+}
+
+// PruneFrom implements field.Serializable interface.
+func (m *Empties) PruneFrom(data []byte) ([]byte, bool) {
+	var ok bool
+	// List
+	m.List = []Empty{}
+	for len(data) > 0 {
+		var e Empty
+		k := len(data)
+		if data, ok = e.PruneFrom(data); !ok {
+			return nil, false
+		}
+		if len(data) == k {
+			return nil, false
+		}
+		m.List = append(m.List, e)
+	}
+	return data, true
+}
@@ -0,0 +1,50 @@
+// Package golden contains the types used to test the code generated
+// by fieldgen.
+package golden
+
+//go:generate go run github.com/yerden/go-util/cmd/fieldgen -type Header,Point,Message,Trailer,Empty,Empties
+
+// Header is a fixed size header.
+type Header struct {
+	Version uint8
+	Flags   uint16 `field:"le"`
+	Length  int    `field:"u24"`
+	Offset  int16
+	_       [2]byte
+	Magic   [4]byte
+	Ready   bool
+}
+
+// Point is a point on the plane.
+type Point struct {
+	X, Y float32
+}
+
+// Message is a variable size message.
+type Message struct {
+	Header
+	Count   uint8
+	Points  []Point `field:"len=Count"`
+	Name    string  `field:"prefix=u8,pad=4"`
+	Values  []int32 `field:"prefix=u16,le"`
+	Codes   [3]uint16
+	Delta   int8
+	Cache   []byte `field:"-"`
+	Payload []byte
+}
+
+// Trailer ends with the list occupying the rest of data.
+type Trailer struct {
+	Kind   uint8
+	Seq    int
+	Mark   uintptr
+	Points []Point
+}
+
+// Empty occupies no data.
+type Empty struct{}
+
+// Empties is the list of elements occupying no data.
+type Empties struct {
+	List []Empty
+}
@@ -0,0 +1,169 @@
+package golden
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yerden/go-util/field"
+)
+
+func TestRoundTrip(t *testing.T) {
+	m := &Message{
+		Header: Header{
+			Version: 1,
+			Flags:   0x0102,
+			Length:  -2,
+			Offset:  -3,
+			Magic:   [4]byte{'G', 'O', 'L', 'D'},
+			Ready:   true,
+		},
+		Points:  []Point{{1.5, -2}, {0, 0.25}},
+		Name:    "hello",
+		Values:  []int32{-1, 0x01020304},
+		Codes:   [3]uint16{1, 2, 0xffff},
+		Delta:   -128,
+		Cache:   []byte{1, 2, 3},
+		Payload: []byte{0xde, 0xad},
+	}
+
+	want := []byte{
+		// Header
+		0x01, 0x02, 0x01, 0xff, 0xff, 0xfe, 0xff, 0xfd,
+		0x00, 0x00, 'G', 'O', 'L', 'D', 0x01,
+		// Count and Points
+		0x02,
+		0x3f, 0xc0, 0x00, 0x00, 0xc0, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x3e, 0x80, 0x00, 0x00,
+		// Name
+		0x05, 'h', 'e', 'l', 'l', 'o', 0x00, 0x00,
+		// Values
+		0x02, 0x00, 0xff, 0xff, 0xff, 0xff, 0x04, 0x03, 0x02, 0x01,
+		// Codes
+		0x00, 0x01, 0x00, 0x02, 0xff, 0xff,
+		// Delta
+		0x80,
+		// Payload
+		0xde, 0xad,
+	}
+
+	data := m.AppendTo([]byte{0xaa})
+	if !bytes.Equal(data[1:], want) {
+		t.Fatalf("encoded %x, want %x", data[1:], want)
+	}
+
+	if n := m.EncodedLen(); n != len(want) {
+		t.Errorf("encoded length %d, want %d", n, len(want))
+	}
+
+	var x Message
+	if rest, ok := x.PruneFrom(data[1:]); !ok || len(rest) != 0 {
+		t.Fatalf("decoding failed: %v, %x", ok, rest)
+	}
+
+	// Count is filled on decoding, Cache is skipped
+	m.Count, m.Cache = 2, nil
+	if !reflect.DeepEqual(&x, m) {
+		t.Errorf("decoded %+v, want %+v", x, *m)
+	}
+
+	// truncated data
+	for i := 0; i < len(want); i++ {
+		var x Message
+		if _, ok := x.PruneFrom(want[:i]); ok && i < len(want)-len(m.Payload) {
+			t.Errorf("truncated to %d: decoding should fail", i)
+		}
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	var m, x Message
+	data := m.AppendTo(nil)
+	if len(data) != m.EncodedLen() {
+		t.Fatalf("encoded %d bytes, want %d", len(data), m.EncodedLen())
+	}
+
+	if rest, ok := x.PruneFrom(data); !ok || len(rest) != 0 {
+		t.Fatalf("decoding failed: %v, %x", ok, rest)
+	}
+
+	// decoded slices are empty but not nil
+	x.Points, x.Values, x.Payload = nil, nil, nil
+	if !reflect.DeepEqual(x, m) {
+		t.Errorf("decoded %+v, want %+v", x, m)
+	}
+}
+
+// The types without generated methods so that field.Marshal encodes
+// them field by field.
+type (
+	header  Header
+	point   Point
+	message Message
+	trailer Trailer
+)
+
+func TestMarshalEquivalence(t *testing.T) {
+	m := &Message{
+		Header: Header{
+			Version: 2,
+			Flags:   0xabcd,
+			Length:  0x123456,
+			Offset:  -300,
+			Magic:   [4]byte{1, 2, 3, 4},
+		},
+		Points:  []Point{{-1.25, 3}},
+		Name:    "abcd",
+		Values:  []int32{7, -7},
+		Codes:   [3]uint16{0, 0x8000, 3},
+		Delta:   5,
+		Payload: []byte{1, 2, 3},
+	}
+	tr := &Trailer{Kind: 9, Seq: -2, Mark: 7, Points: []Point{{1, 2}, {3, 4}}}
+
+	for _, c := range []struct {
+		gen, refl interface{}
+	}{
+		{&m.Header, (*header)(&m.Header)},
+		{&m.Points[0], (*point)(&m.Points[0])},
+		{m, (*message)(m)},
+		{tr, (*trailer)(tr)},
+	} {
+		want, err := field.Marshal(nil, c.refl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := c.gen.(field.Serializable)
+		if data := s.AppendTo(nil); !bytes.Equal(data, want) {
+			t.Errorf("%T: encoded %x, field.Marshal gives %x", s, data, want)
+		}
+
+		// decode generated encoding with field.Unmarshal
+		v := reflect.New(reflect.TypeOf(c.refl).Elem())
+		if rest, err := field.Unmarshal(want, v.Interface()); err != nil || len(rest) != 0 {
+			t.Fatalf("%T: field.Unmarshal failed: %v, %x", s, err, rest)
+		}
+
+		x := reflect.New(reflect.TypeOf(c.gen).Elem())
+		if rest, ok := x.Interface().(field.Serializable).PruneFrom(want); !ok || len(rest) != 0 {
+			t.Fatalf("%T: decoding failed: %v, %x", s, ok, rest)
+		}
+
+		if !reflect.DeepEqual(x.Elem().Convert(v.Elem().Type()).Interface(), v.Elem().Interface()) {
+			t.Errorf("%T: decoded %+v, field.Unmarshal gives %+v", s, x.Elem(), v.Elem())
+		}
+	}
+}
+
+func TestNoProgress(t *testing.T) {
+	var e Empties
+	if _, ok := e.PruneFrom([]byte{1}); ok {
+		t.Error("decoding should fail")
+	}
+
+	data := e.AppendTo(nil)
+	if rest, ok := e.PruneFrom(data); !ok || len(rest) != 0 || len(e.List) != 0 {
+		t.Errorf("decoding failed: %v, %x", ok, rest)
+	}
+}
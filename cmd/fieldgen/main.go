@@ -0,0 +1,92 @@
+/*
+Command fieldgen generates the methods of field.Serializable interface
+for Go structs so that they may be encoded without reflection.
+
+Usage:
+
+	fieldgen -type T1,T2 [-output file] [dir]
+
+For every specified struct type fieldgen generates AppendTo, PruneFrom
+and EncodedLen methods using primitives of field package. The fields
+are annotated with the same "field" tags as used by field.Marshal:
+
+	be, le          - endianness of integers, big endian by default
+	u8, u16, u24,
+	u32, u64        - size of integers, natural size by default
+	len=Count       - the number of elements of slice or string is
+	                  stored in the preceding field Count
+	prefix=u8, u16,
+	u24, u32        - slice or string is prefixed with the number of
+	                  its elements
+	pad=4           - encoded field is padded with zeros to the
+	                  multiple of 4 bytes
+	-               - field is skipped
+
+The fields of other named types, e.g. nested structs, are expected to
+implement AppendTo, PruneFrom and EncodedLen methods themselves. Blank
+fields should be byte arrays and are encoded as zeros. Slice or
+string with neither len nor prefix option occupies all the remaining
+data. Byte slices decoded by PruneFrom refer to the input data.
+
+Unlike field.Marshal the generated AppendTo does not check the ranges
+of values, so the integers are silently truncated to the size of the
+field.
+
+The tool is intended to be used with go generate:
+
+	//go:generate fieldgen -type Header
+
+By default the output is written to <type>_field.go in lower case
+where type is the first specified type.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "Comma-separated list of struct type names; required")
+	output    = flag.String("output", "", "Output file name; default <type>_field.go")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("fieldgen: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: fieldgen -type T1,T2 [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	src, err := generate(dir, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_field.go")
+	}
+
+	if err := ioutil.WriteFile(name, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	dir := filepath.Join("internal", "golden")
+	golden := filepath.Join(dir, "header_field.go")
+
+	src, err := generate(dir, []string{"Header", "Point", "Message", "Trailer", "Empty", "Empties"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(golden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(src, want) {
+		t.Errorf("generated code differs from %s, run go test -update", golden)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, s := range []string{
+		"type T struct { A map[int]int }",
+		"type T struct { A *int }",
+		"type T struct { A []int `field:\"len=B\"`; B int }",
+		"type T struct { B string; A []int `field:\"len=B\"` }",
+		"type T struct { A int `field:\"bogus\"` }",
+		"type T struct { A int `field:\"prefix=u8\"` }",
+		"type T struct { A string `field:\"u16\"` }",
+		"type T struct { A []int `field:\"prefix=u64\"` }",
+		"type T struct { A []byte `field:\"pad=0\"` }",
+		"type T struct { _ int }",
+		"type T struct { A [][]byte }",
+		"type U struct { A int }",
+	} {
+		dir, err := ioutil.TempDir("", "fieldgen")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func(dir string) { os.RemoveAll(dir) }(dir)
+
+		file := filepath.Join(dir, "t.go")
+		if err := ioutil.WriteFile(file, []byte("package t\n"+s+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := generate(dir, []string{"T"}); err == nil {
+			t.Errorf("%s: error expected", s)
+		}
+	}
+}
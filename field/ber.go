@@ -0,0 +1,405 @@
+package field
+
+import (
+	"github.com/yerden/go-util/bcd"
+)
+
+// Classes of BER tag.
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+	ClassPrivate     byte = 0xc0
+)
+
+// Numbers of universal BER tags.
+const (
+	TagEOC         uint32 = 0x00
+	TagBoolean     uint32 = 0x01
+	TagInteger     uint32 = 0x02
+	TagBitString   uint32 = 0x03
+	TagOctetString uint32 = 0x04
+	TagNull        uint32 = 0x05
+	TagOID         uint32 = 0x06
+	TagEnumerated  uint32 = 0x0a
+	TagUTF8String  uint32 = 0x0c
+	TagSequence    uint32 = 0x10
+	TagSet         uint32 = 0x11
+)
+
+// IndefiniteLength is the length of BER data object which contents
+// are terminated with end-of-contents octets.
+const IndefiniteLength = -1
+
+// maximum number of octets in long form of length
+const maxLengthOctets = 4
+
+// constructed bit of the identifier octet
+const constructed = 0x20
+
+// Tag is the identifier of BER data object.
+type Tag struct {
+	// Class is one of ClassUniversal, ClassApplication,
+	// ClassContext or ClassPrivate.
+	Class byte
+	// Constructed is true if the contents of data object consists of
+	// nested data objects.
+	Constructed bool
+	// Number of the tag.
+	Number uint32
+}
+
+// WriteTag appends BER encoded tag t to data and returns the
+// resulting slice.
+func WriteTag(data []byte, t Tag) []byte {
+	id := t.Class & 0xc0
+	if t.Constructed {
+		id |= constructed
+	}
+
+	if t.Number < 0x1f {
+		return append(data, id|byte(t.Number))
+	}
+
+	data = append(data, id|0x1f)
+	n := 0
+	for x := t.Number; x > 0; x >>= 7 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		b := byte(t.Number>>(7*uint(i))) & 0x7f
+		if i > 0 {
+			b |= 0x80
+		}
+		data = append(data, b)
+	}
+	return data
+}
+
+// ReadTag reads BER encoded tag from the top of data and puts it
+// into t. Returns the resulting slice and true if reading was ok.
+func ReadTag(data []byte, t *Tag) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	id := data[0]
+	t.Class = id & 0xc0
+	t.Constructed = id&constructed != 0
+	if t.Number = uint32(id & 0x1f); t.Number < 0x1f {
+		return data[1:], true
+	}
+
+	t.Number = 0
+	for i := 1; i < len(data); i++ {
+		if t.Number > 0xffffffff>>7 {
+			// overflow
+			return nil, false
+		}
+		t.Number = t.Number<<7 | uint32(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return data[i+1:], true
+		}
+	}
+	return nil, false
+}
+
+// WriteBERLength appends BER encoded length n to data and returns
+// the resulting slice. The shortest form of length is used as
+// required by DER. If n is IndefiniteLength the indefinite form is
+// used.
+func WriteBERLength(data []byte, n int) []byte {
+	if n < 0 {
+		return append(data, 0x80)
+	}
+
+	if n < 0x80 {
+		return append(data, byte(n))
+	}
+
+	k := 0
+	for x := n; x > 0; x >>= 8 {
+		k++
+	}
+
+	data = append(data, 0x80|byte(k))
+	for i := k - 1; i >= 0; i-- {
+		data = append(data, byte(n>>(8*uint(i))))
+	}
+	return data
+}
+
+// ReadBERLength reads BER encoded length from the top of data and
+// puts it into n. Indefinite form of length is read as
+// IndefiniteLength. Returns the resulting slice and true if reading
+// was ok.
+func ReadBERLength(data []byte, n *int) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	b := data[0]
+	switch {
+	case b < 0x80:
+		*n = int(b)
+		return data[1:], true
+	case b == 0x80:
+		*n = IndefiniteLength
+		return data[1:], true
+	}
+
+	k := int(b & 0x7f)
+	if k > maxLengthOctets || len(data) < 1+k {
+		return nil, false
+	}
+
+	x := 0
+	for _, b := range data[1 : 1+k] {
+		x = x<<8 | int(b)
+	}
+
+	if x < 0 {
+		return nil, false
+	}
+
+	*n = x
+	return data[1+k:], true
+}
+
+// TLV is BER encoded data object.
+type TLV struct {
+	Tag
+
+	// Value is the contents of data object. If the data object is
+	// read from the input Value refers to the input. The
+	// end-of-contents octets are not included.
+	Value []byte
+
+	// Indefinite is true if the data object is read from the input
+	// with the indefinite length.
+	Indefinite bool
+}
+
+var _ Serializable = (*TLV)(nil)
+
+// WriteTLV appends BER data object of tag t and contents value to
+// data using definite length. Returns the resulting slice.
+func WriteTLV(data []byte, t Tag, value []byte) []byte {
+	data = WriteTag(data, t)
+	data = WriteBERLength(data, len(value))
+	return append(data, value...)
+}
+
+// isEOC tells if data starts with end-of-contents octets.
+func isEOC(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0 && data[1] == 0
+}
+
+// ReadTLV reads BER data object from the top of data and puts it
+// into tlv. The value of tlv refers to data. Both definite and
+// indefinite lengths are supported. Returns the resulting slice and
+// true if reading was ok.
+func ReadTLV(data []byte, tlv *TLV) ([]byte, bool) {
+	var n int
+	var ok bool
+
+	if data, ok = ReadTag(data, &tlv.Tag); !ok {
+		return nil, false
+	}
+
+	if data, ok = ReadBERLength(data, &n); !ok {
+		return nil, false
+	}
+
+	if tlv.Indefinite = n == IndefiniteLength; !tlv.Indefinite {
+		return ReadBytes(data, &tlv.Value, n)
+	}
+
+	if !tlv.Constructed {
+		// only constructed encoding may have indefinite length
+		return nil, false
+	}
+
+	// walk nested data objects until end-of-contents
+	var nested TLV
+	for rest := data; ; {
+		if isEOC(rest) {
+			tlv.Value = data[:len(data)-len(rest)]
+			return rest[2:], true
+		}
+
+		if rest, ok = ReadTLV(rest, &nested); !ok {
+			return nil, false
+		}
+	}
+}
+
+// AppendTo implements Serializable interface. The data object is
+// written using definite length.
+func (tlv *TLV) AppendTo(data []byte) []byte {
+	return WriteTLV(data, tlv.Tag, tlv.Value)
+}
+
+// PruneFrom implements Serializable interface. See ReadTLV.
+func (tlv *TLV) PruneFrom(data []byte) ([]byte, bool) {
+	return ReadTLV(data, tlv)
+}
+
+// TLVIter iterates over consecutive BER data objects, e.g. the
+// contents of constructed data object.
+type TLVIter struct {
+	data []byte
+	ok   bool
+}
+
+// NewTLVIter returns the iterator over BER data objects in data.
+func NewTLVIter(data []byte) *TLVIter {
+	return &TLVIter{data: data, ok: true}
+}
+
+// Next reads the next data object into tlv. It returns false if
+// there are no more data objects or the data object is malformed.
+func (it *TLVIter) Next(tlv *TLV) bool {
+	if len(it.data) == 0 || !it.ok {
+		return false
+	}
+
+	it.data, it.ok = ReadTLV(it.data, tlv)
+	return it.ok
+}
+
+// Ok tells if all data objects were read successfully so far.
+func (it *TLVIter) Ok() bool {
+	return it.ok
+}
+
+// BeginTLV appends the tag of constructed data object which length
+// is not known in advance. It returns the resulting slice and the
+// mark which should be passed to EndTLV after the contents is
+// appended.
+func BeginTLV(data []byte, t Tag) ([]byte, int) {
+	data = WriteTag(data, t)
+	return append(data, 0), len(data)
+}
+
+// EndTLV writes the length of data object started with BeginTLV.
+// Returns the resulting slice.
+func EndTLV(data []byte, mark int) []byte {
+	n := len(data) - mark - 1
+	var buf [9]byte
+	l := WriteBERLength(buf[:0], n)
+
+	if extra := len(l) - 1; extra > 0 {
+		// move contents to make room for long form
+		data = append(data, l[1:]...)
+		copy(data[mark+len(l):], data[mark+1:mark+1+n])
+	}
+
+	copy(data[mark:], l)
+	return data
+}
+
+// WriteEOC appends end-of-contents octets which terminate the
+// contents of data object of indefinite length. Returns the
+// resulting slice.
+func WriteEOC(data []byte) []byte {
+	return append(data, 0, 0)
+}
+
+// WriteInteger appends BER data object of tag t with INTEGER or
+// ENUMERATED contents x to data. Returns the resulting slice.
+func WriteInteger(data []byte, t Tag, x int64) []byte {
+	n := 1
+	for y := x; y > 0x7f || y < -0x80; y >>= 8 {
+		n++
+	}
+
+	data = WriteTag(data, t)
+	data = WriteBERLength(data, n)
+	for i := n - 1; i >= 0; i-- {
+		data = append(data, byte(x>>(8*uint(i))))
+	}
+	return data
+}
+
+// Int64 decodes INTEGER or ENUMERATED contents of primitive data
+// object. Returns false if the contents is empty or does not fit
+// into int64.
+func (tlv *TLV) Int64() (int64, bool) {
+	v := tlv.Value
+	if tlv.Constructed || len(v) == 0 || len(v) > 8 {
+		return 0, false
+	}
+
+	// sign extension
+	x := int64(int8(v[0]))
+	for _, b := range v[1:] {
+		x = x<<8 | int64(b)
+	}
+	return x, true
+}
+
+// WriteOctetString appends BER data object of tag t with OCTET
+// STRING contents b to data. Returns the resulting slice.
+func WriteOctetString(data []byte, t Tag, b []byte) []byte {
+	return WriteTLV(data, t, b)
+}
+
+// OctetString returns OCTET STRING contents of data object. The
+// contents of primitive data object is returned as is. Segments of
+// constructed data object are concatenated into new slice.
+func (tlv *TLV) OctetString() ([]byte, bool) {
+	if !tlv.Constructed {
+		return tlv.Value, true
+	}
+
+	var b []byte
+	var seg TLV
+	it := NewTLVIter(tlv.Value)
+	for it.Next(&seg) {
+		s, ok := seg.OctetString()
+		if !ok {
+			return nil, false
+		}
+		b = append(b, s...)
+	}
+
+	if !it.Ok() {
+		return nil, false
+	}
+	return b, true
+}
+
+var tbcd = bcd.NewCodec(bcd.Telephony)
+
+// WriteTBCDString appends BER data object of tag t with TBCD-STRING
+// contents s as in 3GPP TS 29.002 to data. The extended slice and
+// possible error is returned. In case of error data is returned
+// unchanged.
+func WriteTBCDString(data []byte, t Tag, s string) ([]byte, error) {
+	start := len(data)
+	n := bcd.EncodedLen(len(s))
+	data = WriteTag(data, t)
+	data = WriteBERLength(data, n)
+	data, err := tbcd.AppendEncode(data, []byte(s))
+	if err != nil {
+		return data[:start], err
+	}
+	return data, nil
+}
+
+// TBCDString decodes TBCD-STRING contents of data object as in 3GPP
+// TS 29.002.
+func (tlv *TLV) TBCDString() (string, bool) {
+	b, ok := tlv.OctetString()
+	if !ok {
+		return "", false
+	}
+
+	s := make([]byte, bcd.DecodedLen(len(b)))
+	n, err := tbcd.Decode(s, b)
+	if err != nil {
+		return "", false
+	}
+	return string(s[:n]), true
+}
@@ -0,0 +1,167 @@
+package field
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math"
+	"testing"
+)
+
+func TestBERTag(t *testing.T) {
+	for _, tag := range []Tag{
+		{ClassUniversal, false, TagInteger},
+		{ClassContext, true, 30},
+		{ClassApplication, false, 31},
+		{ClassPrivate, true, 0x4000},
+		{ClassContext, false, math.MaxUint32},
+	} {
+		data := WriteTag([]byte{0xaa}, tag)
+		var tag2 Tag
+		res, ok := ReadTag(data[1:], &tag2)
+		assert(t, ok && len(res) == 0 && tag2 == tag, tag, data)
+	}
+
+	data := WriteTag(nil, Tag{ClassContext, true, 0x81})
+	assert(t, bytes.Equal(data, []byte{0xbf, 0x81, 0x01}), data)
+
+	var tag Tag
+	_, ok := ReadTag([]byte{0x1f, 0x81}, &tag)
+	assert(t, !ok)
+	_, ok = ReadTag([]byte{0x1f, 0x9f, 0xff, 0xff, 0xff, 0x7f}, &tag)
+	assert(t, !ok)
+}
+
+func TestBERLength(t *testing.T) {
+	for _, n := range []int{0, 1, 0x7f, 0x80, 0xff, 0x100, 0xffffff, IndefiniteLength} {
+		data := WriteBERLength(nil, n)
+		var m int
+		res, ok := ReadBERLength(data, &m)
+		assert(t, ok && len(res) == 0 && m == n, n, data)
+	}
+
+	assert(t, bytes.Equal(WriteBERLength(nil, 0x80), []byte{0x81, 0x80}))
+	assert(t, bytes.Equal(WriteBERLength(nil, 0x1234), []byte{0x82, 0x12, 0x34}))
+
+	var n int
+	_, ok := ReadBERLength([]byte{0x82, 0x01}, &n)
+	assert(t, !ok)
+	_, ok = ReadBERLength([]byte{0x85, 1, 2, 3, 4, 5}, &n)
+	assert(t, !ok)
+}
+
+func TestTLV(t *testing.T) {
+	// SEQUENCE { INTEGER 5, [0] OCTET STRING, [1] SEQUENCE (indefinite) { NULL } }
+	data := []byte{
+		0x30, 0x80,
+		0x02, 0x01, 0x05,
+		0x80, 0x02, 0xaa, 0xbb,
+		0xa1, 0x80, 0x05, 0x00, 0x00, 0x00,
+		0x00, 0x00,
+		0xff,
+	}
+
+	var seq TLV
+	res, ok := ReadTLV(data, &seq)
+	assert(t, ok && bytes.Equal(res, []byte{0xff}), res)
+	assert(t, seq.Indefinite && seq.Constructed && seq.Number == TagSequence)
+	assert(t, len(seq.Value) == 13, seq.Value)
+
+	var tlv TLV
+	it := NewTLVIter(seq.Value)
+	assert(t, it.Next(&tlv))
+	x, ok := tlv.Int64()
+	assert(t, ok && x == 5)
+
+	assert(t, it.Next(&tlv))
+	assert(t, tlv.Class == ClassContext && tlv.Number == 0)
+	assert(t, bytes.Equal(tlv.Value, []byte{0xaa, 0xbb}))
+
+	assert(t, it.Next(&tlv))
+	assert(t, tlv.Indefinite && bytes.Equal(tlv.Value, []byte{0x05, 0x00}))
+
+	assert(t, !it.Next(&tlv) && it.Ok())
+
+	// missing end-of-contents
+	_, ok = ReadTLV(data[:15], &tlv)
+	assert(t, !ok)
+
+	// primitive with indefinite length
+	_, ok = ReadTLV([]byte{0x04, 0x80, 0x00, 0x00}, &tlv)
+	assert(t, !ok)
+
+	it = NewTLVIter([]byte{0x04, 0x01, 0x00, 0x04, 0x05})
+	assert(t, it.Next(&tlv) && !it.Next(&tlv) && !it.Ok())
+}
+
+func TestBERBuilder(t *testing.T) {
+	seq := Tag{Constructed: true, Number: TagSequence}
+	data, mark := BeginTLV(nil, seq)
+	data = WriteInteger(data, Tag{Number: TagInteger}, -129)
+	data = WriteOctetString(data, Tag{Number: TagOctetString}, make([]byte, 200))
+	data = EndTLV(data, mark)
+
+	var v struct {
+		X int
+		B []byte
+	}
+	rest, err := asn1.Unmarshal(data, &v)
+	assert(t, err == nil && len(rest) == 0, err)
+	assert(t, v.X == -129 && len(v.B) == 200, v.X)
+
+	// short contents
+	data, mark = BeginTLV(data[:0], seq)
+	data = WriteInteger(data, Tag{Number: TagInteger}, 1)
+	data = EndTLV(data, mark)
+	assert(t, bytes.Equal(data, []byte{0x30, 0x03, 0x02, 0x01, 0x01}), data)
+
+	// indefinite length
+	data = WriteTag(data[:0], seq)
+	data = WriteBERLength(data, IndefiniteLength)
+	data = WriteInteger(data, Tag{Number: TagEnumerated}, 3)
+	data = WriteEOC(data)
+	assert(t, bytes.Equal(data, []byte{0x30, 0x80, 0x0a, 0x01, 0x03, 0x00, 0x00}), data)
+}
+
+func TestBERInteger(t *testing.T) {
+	for _, x := range []int64{0, 1, -1, 127, 128, -128, -129, 0x7fff, math.MaxInt64, math.MinInt64} {
+		data := WriteInteger(nil, Tag{Number: TagInteger}, x)
+		expected, _ := asn1.Marshal(x)
+		assert(t, bytes.Equal(data, expected), x, data)
+
+		var tlv TLV
+		_, ok := ReadTLV(data, &tlv)
+		y, ok2 := tlv.Int64()
+		assert(t, ok && ok2 && x == y, x)
+	}
+
+	tlv := TLV{Value: make([]byte, 9)}
+	_, ok := tlv.Int64()
+	assert(t, !ok)
+}
+
+func TestBEROctetString(t *testing.T) {
+	// constructed OCTET STRING of two segments
+	data := []byte{0x24, 0x80, 0x04, 0x02, 0x21, 0x43, 0x04, 0x01, 0xf5, 0x00, 0x00}
+	var tlv TLV
+	_, ok := ReadTLV(data, &tlv)
+	assert(t, ok)
+
+	b, ok := tlv.OctetString()
+	assert(t, ok && bytes.Equal(b, []byte{0x21, 0x43, 0xf5}), b)
+
+	s, ok := tlv.TBCDString()
+	assert(t, ok && s == "12345", s)
+
+	data, err := WriteTBCDString(nil, Tag{Class: ClassContext, Number: 2}, "79001234567")
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data, []byte{0x82, 0x06, 0x97, 0x00, 0x21, 0x43, 0x65, 0xf7}), data)
+
+	_, ok = ReadTLV(data, &tlv)
+	s, ok2 := tlv.TBCDString()
+	assert(t, ok && ok2 && s == "79001234567", s)
+
+	prefix := []byte{0x04, 0x01, 0xaa}
+	data, err = WriteTBCDString(prefix, Tag{Number: TagOctetString}, "12x")
+	assert(t, err != nil)
+	assert(t, bytes.Equal(data, prefix), data)
+}
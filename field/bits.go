@@ -0,0 +1,201 @@
+package field
+
+// BitOrder specifies the order in which bits are taken from octets.
+type BitOrder int
+
+const (
+	// MSBFirst takes bits starting from the most significant bit of
+	// octet. The first bit read is the most significant bit of the
+	// value. This is the order of network protocols, e.g. IPv4
+	// header flags.
+	MSBFirst BitOrder = iota
+
+	// LSBFirst takes bits starting from the least significant bit
+	// of octet. The first bit read is the least significant bit of
+	// the value, e.g. as in DEFLATE.
+	LSBFirst
+)
+
+// MaxBits is the maximum width of the field read or written at
+// once.
+const MaxBits = 64
+
+// mask returns the value with n least significant bits set.
+func mask(n int) uint64 {
+	if n >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<uint(n) - 1
+}
+
+// BitReader reads the fields of arbitrary bit width from the data.
+type BitReader struct {
+	data  []byte
+	order BitOrder
+
+	// number of bits consumed in data[0], 0..7
+	bit int
+}
+
+// NewBitReader returns the BitReader over data with specified bit
+// order.
+func NewBitReader(data []byte, order BitOrder) *BitReader {
+	return &BitReader{data: data, order: order}
+}
+
+// Len returns the number of unread bits.
+func (r *BitReader) Len() int {
+	return len(r.data)*8 - r.bit
+}
+
+// Aligned tells if the reader is at the octet boundary.
+func (r *BitReader) Aligned() bool {
+	return r.bit == 0
+}
+
+// Align skips the rest of partially read octet, if any.
+func (r *BitReader) Align() {
+	if r.bit != 0 {
+		r.data, r.bit = r.data[1:], 0
+	}
+}
+
+// Bytes returns unread data starting from the next octet boundary.
+// The rest of partially read octet is skipped. Bytes may be used to
+// continue reading octet-aligned fields with ReadUint16 etc.
+func (r *BitReader) Bytes() []byte {
+	r.Align()
+	return r.data
+}
+
+// ReadBits reads n bits into x. It returns false if n exceeds
+// MaxBits or there are less than n unread bits, in which case the
+// reader and x are not modified.
+func (r *BitReader) ReadBits(n int, x *uint64) bool {
+	if n < 0 || n > MaxBits || n > r.Len() {
+		return false
+	}
+
+	var y uint64
+	for shift := 0; n > 0; {
+		k := 8 - r.bit
+		if k > n {
+			k = n
+		}
+
+		if r.order == MSBFirst {
+			v := uint64(r.data[0]>>uint(8-r.bit-k)) & mask(k)
+			y = y<<uint(k) | v
+		} else {
+			v := uint64(r.data[0]>>uint(r.bit)) & mask(k)
+			y |= v << uint(shift)
+			shift += k
+		}
+
+		if r.bit += k; r.bit == 8 {
+			r.data, r.bit = r.data[1:], 0
+		}
+		n -= k
+	}
+
+	*x = y
+	return true
+}
+
+// ReadBool reads one bit into x. It returns false if there are no
+// unread bits.
+func (r *BitReader) ReadBool(x *bool) bool {
+	var y uint64
+	if !r.ReadBits(1, &y) {
+		return false
+	}
+	*x = y != 0
+	return true
+}
+
+// Skip skips n bits. It returns false if there are less than n
+// unread bits, in which case the reader is not modified.
+func (r *BitReader) Skip(n int) bool {
+	if n < 0 || n > r.Len() {
+		return false
+	}
+
+	n += r.bit
+	r.data, r.bit = r.data[n/8:], n%8
+	return true
+}
+
+// BitWriter appends the fields of arbitrary bit width to the data.
+type BitWriter struct {
+	data  []byte
+	order BitOrder
+
+	// number of bits used in the last octet of data, 0..7
+	bit int
+}
+
+// NewBitWriter returns the BitWriter which appends to data with
+// specified bit order.
+func NewBitWriter(data []byte, order BitOrder) *BitWriter {
+	return &BitWriter{data: data, order: order}
+}
+
+// Aligned tells if the writer is at the octet boundary.
+func (w *BitWriter) Aligned() bool {
+	return w.bit == 0
+}
+
+// Align pads partially written octet with zero bits, if any.
+func (w *BitWriter) Align() {
+	w.bit = 0
+}
+
+// Bytes returns the resulting slice. Partially written octet is
+// padded with zero bits so the further writes start from the next
+// octet. The slice may be extended with octet-aligned fields, e.g.
+// with WriteUint16, and passed to NewBitWriter to continue.
+func (w *BitWriter) Bytes() []byte {
+	w.Align()
+	return w.data
+}
+
+// WriteBits appends n least significant bits of x. WriteBits panics
+// if n exceeds MaxBits.
+func (w *BitWriter) WriteBits(x uint64, n int) {
+	if n < 0 || n > MaxBits {
+		panic("invalid bit width")
+	}
+
+	x &= mask(n)
+	for n > 0 {
+		if w.bit == 0 {
+			w.data = append(w.data, 0)
+		}
+
+		k := 8 - w.bit
+		if k > n {
+			k = n
+		}
+
+		last := &w.data[len(w.data)-1]
+		if w.order == MSBFirst {
+			v := byte(x>>uint(n-k)) & byte(mask(k))
+			*last |= v << uint(8-w.bit-k)
+		} else {
+			*last |= byte(x&mask(k)) << uint(w.bit)
+			x >>= uint(k)
+		}
+
+		w.bit = (w.bit + k) % 8
+		n -= k
+	}
+}
+
+// WriteBool appends one bit which is set if x is true.
+func (w *BitWriter) WriteBool(x bool) {
+	var y uint64
+	if x {
+		y = 1
+	}
+	w.WriteBits(y, 1)
+}
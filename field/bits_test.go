@@ -0,0 +1,110 @@
+package field
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestBitReaderMSB(t *testing.T) {
+	// IPv4 header: version, IHL, DSCP, ECN, ..., flags and fragment
+	// offset
+	r := NewBitReader([]byte{0x45, 0xb9, 0x40, 0x01, 0xaa}, MSBFirst)
+
+	var x uint64
+	var b bool
+	assert(t, r.ReadBits(4, &x) && x == 4, x)
+	assert(t, r.ReadBits(4, &x) && x == 5, x)
+	assert(t, r.ReadBits(6, &x) && x == 46, x)
+	assert(t, r.ReadBits(2, &x) && x == 1, x)
+	assert(t, r.Aligned())
+	assert(t, r.ReadBool(&b) && !b)
+	assert(t, r.ReadBool(&b) && b)
+	assert(t, r.ReadBool(&b) && !b)
+	assert(t, !r.Aligned())
+	assert(t, r.ReadBits(13, &x) && x == 1, x)
+	assert(t, r.Len() == 8)
+
+	data := r.Bytes()
+	assert(t, bytes.Equal(data, []byte{0xaa}))
+
+	// truncation
+	x = 7
+	assert(t, !r.ReadBits(9, &x) && x == 7)
+	assert(t, !r.ReadBits(MaxBits+1, &x))
+	assert(t, r.Len() == 8)
+	assert(t, r.Skip(3) && r.Len() == 5)
+	assert(t, r.ReadBits(5, &x) && x == 0xa, x)
+	assert(t, r.Len() == 0 && !r.Skip(1) && !r.ReadBool(&b))
+}
+
+func TestBitReaderLSB(t *testing.T) {
+	r := NewBitReader([]byte{0xb5, 0x0f, 0x12, 0x34}, LSBFirst)
+
+	var x uint64
+	assert(t, r.ReadBits(1, &x) && x == 1)
+	assert(t, r.ReadBits(3, &x) && x == 2, x)
+	assert(t, r.ReadBits(8, &x) && x == 0xfb, x)
+	assert(t, !r.Aligned())
+
+	var y uint16
+	data, ok := BigEndian.ReadUint16(r.Bytes(), &y)
+	assert(t, ok && y == 0x1234 && len(data) == 0)
+}
+
+func TestBitWriter(t *testing.T) {
+	w := NewBitWriter([]byte{0xff}, MSBFirst)
+	w.WriteBits(4, 4)
+	w.WriteBits(5, 4)
+	w.WriteBits(46, 6)
+	w.WriteBits(1, 2)
+	assert(t, w.Aligned())
+	w.WriteBool(false)
+	w.WriteBool(true)
+	w.WriteBool(false)
+	w.WriteBits(0xe001, 13)
+	assert(t, bytes.Equal(w.Bytes(), []byte{0xff, 0x45, 0xb9, 0x40, 0x01}), w.Bytes())
+
+	w = NewBitWriter(nil, LSBFirst)
+	w.WriteBits(1, 1)
+	w.WriteBits(2, 3)
+	w.WriteBits(0xfb, 8)
+	assert(t, !w.Aligned())
+	data := BigEndian.WriteUint16(w.Bytes(), 0x1234)
+	assert(t, bytes.Equal(data, []byte{0xb5, 0x0f, 0x12, 0x34}), data)
+
+	w = NewBitWriter(data, LSBFirst)
+	w.WriteBool(true)
+	assert(t, bytes.Equal(w.Bytes(), []byte{0xb5, 0x0f, 0x12, 0x34, 0x01}))
+
+	defer func() {
+		assert(t, recover() != nil)
+	}()
+	w.WriteBits(0, MaxBits+1)
+}
+
+func TestBitRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	widths := make([]int, 100)
+	values := make([]uint64, len(widths))
+	for i := range widths {
+		widths[i] = rnd.Intn(MaxBits + 1)
+		values[i] = rnd.Uint64() & mask(widths[i])
+	}
+
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		w := NewBitWriter(nil, order)
+		for i, n := range widths {
+			w.WriteBits(values[i], n)
+		}
+
+		r := NewBitReader(w.Bytes(), order)
+		for i, n := range widths {
+			var x uint64
+			assert(t, r.ReadBits(n, &x), i)
+			assert(t, x == values[i], order, n, x, values[i])
+		}
+		assert(t, r.Len() < 8)
+	}
+}
@@ -19,14 +19,17 @@ type bigEndian struct{}
 
 type littleEndian struct{}
 
-// Endianness allows to read/write integer fields with different
-// endianness.
+// Endianness allows to read/write integer and floating-point fields
+// with different endianness.
 type Endianness interface {
 	// Append integer number to specified slice and return resulting
 	// slice.
 	WriteUint16([]byte, uint16) []byte
 	WriteUint24([]byte, uint32) []byte
 	WriteUint32([]byte, uint32) []byte
+	WriteUint40([]byte, uint64) []byte
+	WriteUint48([]byte, uint64) []byte
+	WriteUint56([]byte, uint64) []byte
 	WriteUint64([]byte, uint64) []byte
 
 	// Read integer value of specified length from the top of the
@@ -35,7 +38,27 @@ type Endianness interface {
 	ReadUint16([]byte, *uint16) ([]byte, bool)
 	ReadUint24([]byte, *uint32) ([]byte, bool)
 	ReadUint32([]byte, *uint32) ([]byte, bool)
+	ReadUint40([]byte, *uint64) ([]byte, bool)
+	ReadUint48([]byte, *uint64) ([]byte, bool)
+	ReadUint56([]byte, *uint64) ([]byte, bool)
 	ReadUint64([]byte, *uint64) ([]byte, bool)
+
+	// Signed integers are written/read in two's complement.
+	WriteInt8([]byte, int8) []byte
+	WriteInt16([]byte, int16) []byte
+	WriteInt32([]byte, int32) []byte
+	WriteInt64([]byte, int64) []byte
+	ReadInt8([]byte, *int8) ([]byte, bool)
+	ReadInt16([]byte, *int16) ([]byte, bool)
+	ReadInt32([]byte, *int32) ([]byte, bool)
+	ReadInt64([]byte, *int64) ([]byte, bool)
+
+	// Floating-point numbers are written/read in IEEE 754 binary
+	// format.
+	WriteFloat32([]byte, float32) []byte
+	WriteFloat64([]byte, float64) []byte
+	ReadFloat32([]byte, *float32) ([]byte, bool)
+	ReadFloat64([]byte, *float64) ([]byte, bool)
 }
 
 var _ Endianness = BigEndian
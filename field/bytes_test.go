@@ -2,6 +2,7 @@ package field
 
 import (
 	"bytes"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
@@ -166,3 +167,89 @@ func BenchmarkWriteUint32(b *testing.B) {
 		data = BigEndian.WriteUint32(data[:0], x)
 	}
 }
+
+func TestWideUint(t *testing.T) {
+	x := uint64(0x0102030405060708)
+	for _, c := range []struct {
+		order Endianness
+		write func([]byte, uint64) []byte
+		read  func([]byte, *uint64) ([]byte, bool)
+		want  []byte
+	}{
+		{BigEndian, BigEndian.WriteUint40, BigEndian.ReadUint40, []byte{4, 5, 6, 7, 8}},
+		{BigEndian, BigEndian.WriteUint48, BigEndian.ReadUint48, []byte{3, 4, 5, 6, 7, 8}},
+		{BigEndian, BigEndian.WriteUint56, BigEndian.ReadUint56, []byte{2, 3, 4, 5, 6, 7, 8}},
+		{LittleEndian, LittleEndian.WriteUint40, LittleEndian.ReadUint40, []byte{8, 7, 6, 5, 4}},
+		{LittleEndian, LittleEndian.WriteUint48, LittleEndian.ReadUint48, []byte{8, 7, 6, 5, 4, 3}},
+		{LittleEndian, LittleEndian.WriteUint56, LittleEndian.ReadUint56, []byte{8, 7, 6, 5, 4, 3, 2}},
+	} {
+		data := c.write([]byte{0xaa}, x)
+		assert(t, bytes.Equal(data, append([]byte{0xaa}, c.want...)), data)
+
+		var y uint64
+		res, ok := c.read(append(c.want, 0xbb), &y)
+		assert(t, ok)
+		assert(t, bytes.Equal(res, []byte{0xbb}))
+		assert(t, y == x&(1<<(8*uint(len(c.want)))-1), y)
+
+		y = 1
+		res, ok = c.read(c.want[1:], &y)
+		assert(t, !ok && len(res) == 0)
+		assert(t, y == 1)
+	}
+}
+
+func TestSigned(t *testing.T) {
+	for _, order := range []Endianness{BigEndian, LittleEndian} {
+		data := order.WriteInt8(nil, -2)
+		data = order.WriteInt16(data, -3)
+		data = order.WriteInt32(data, -4)
+		data = order.WriteInt64(data, -5)
+		assert(t, len(data) == 15)
+
+		var x8 int8
+		var x16 int16
+		var x32 int32
+		var x64 int64
+		res, ok := order.ReadInt8(data, &x8)
+		assert(t, ok && x8 == -2, x8)
+		res, ok = order.ReadInt16(res, &x16)
+		assert(t, ok && x16 == -3, x16)
+		res, ok = order.ReadInt32(res, &x32)
+		assert(t, ok && x32 == -4, x32)
+		res, ok = order.ReadInt64(res, &x64)
+		assert(t, ok && x64 == -5, x64)
+		assert(t, len(res) == 0)
+
+		_, ok = order.ReadInt64(data[:7], &x64)
+		assert(t, !ok && x64 == -5)
+	}
+
+	data := BigEndian.WriteInt16(nil, -2)
+	assert(t, bytes.Equal(data, []byte{0xff, 0xfe}))
+	data = LittleEndian.WriteInt32(nil, -2)
+	assert(t, bytes.Equal(data, []byte{0xfe, 0xff, 0xff, 0xff}))
+}
+
+func TestFloat(t *testing.T) {
+	data := BigEndian.WriteFloat32(nil, 1.5)
+	assert(t, bytes.Equal(data, []byte{0x3f, 0xc0, 0, 0}), data)
+	data = LittleEndian.WriteFloat64(nil, -2)
+	assert(t, bytes.Equal(data, []byte{0, 0, 0, 0, 0, 0, 0, 0xc0}), data)
+
+	for _, order := range []Endianness{BigEndian, LittleEndian} {
+		data := order.WriteFloat32(nil, -0.25)
+		data = order.WriteFloat64(data, math.Pi)
+
+		var x32 float32
+		var x64 float64
+		res, ok := order.ReadFloat32(data, &x32)
+		assert(t, ok && x32 == -0.25, x32)
+		res, ok = order.ReadFloat64(res, &x64)
+		assert(t, ok && x64 == math.Pi, x64)
+		assert(t, len(res) == 0)
+
+		_, ok = order.ReadFloat64(data[4:11], &x64)
+		assert(t, !ok && x64 == math.Pi)
+	}
+}
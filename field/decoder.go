@@ -0,0 +1,292 @@
+package field
+
+import (
+	"fmt"
+)
+
+// ErrBadValue is recorded by Decoder if the value fails to decode
+// with its PruneFrom method. Serializable does not tell whether the
+// data is short or malformed.
+var ErrBadValue = fmt.Errorf("invalid value")
+
+// Error describes the failure of Decoder or Encoder. Error wraps
+// the cause so it can be matched with errors.Is.
+type Error struct {
+	// Offset of the failed field from the beginning of the data.
+	Offset int
+
+	// Name of the failed field, if specified.
+	Field string
+
+	// The cause of the failure, e.g. ErrShortData.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("field %s: %v at offset %d", e.Field, e.Err, e.Offset)
+}
+
+// Unwrap returns the underlying error value.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads consecutive fields from the data. The first failure
+// is recorded and all subsequent reads do nothing so the fields may
+// be read in a straight sequence of calls followed by a single Err
+// check:
+//
+//	d := field.NewDecoder(data, field.BigEndian)
+//	d.Field("version").Uint8(&h.Version)
+//	d.Field("length").Uint16(&h.Length)
+//	d.Field("payload").Bytes(&h.Payload, int(h.Length))
+//	if err := d.Err(); err != nil {
+//		return err
+//	}
+//
+// If the read fails the destination value is not modified.
+type Decoder struct {
+	data  []byte
+	off   int
+	order Endianness
+
+	// name of the next field
+	name string
+	err  error
+}
+
+// NewDecoder returns the Decoder of data. Multi-byte integers and
+// floats are read with specified endianness.
+func NewDecoder(data []byte, order Endianness) *Decoder {
+	return &Decoder{data: data, order: order}
+}
+
+// Err returns the first failure as *Error, or nil.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Offset returns the number of bytes read so far.
+func (d *Decoder) Offset() int {
+	return d.off
+}
+
+// Len returns the number of unread bytes.
+func (d *Decoder) Len() int {
+	return len(d.data)
+}
+
+// Rest returns unread data.
+func (d *Decoder) Rest() []byte {
+	return d.data
+}
+
+// Field names the next field read so that it is reported in the
+// error. It returns d so that calls may be chained.
+func (d *Decoder) Field(name string) *Decoder {
+	d.name = name
+	return d
+}
+
+// Fail records err at the current offset unless another failure is
+// already recorded. It is useful to report invalid values, e.g.
+// unknown version of the protocol. The pending field name is used
+// in the error.
+func (d *Decoder) Fail(err error) {
+	if d.err == nil {
+		d.err = &Error{Offset: d.off, Field: d.name, Err: err}
+	}
+}
+
+// ok tells if the decoding may proceed.
+func (d *Decoder) ok() bool {
+	return d.err == nil
+}
+
+// advance moves to rest of data if the read was ok or records the
+// failure otherwise.
+func (d *Decoder) advance(rest []byte, ok bool) {
+	if !ok {
+		d.Fail(ErrShortData)
+		return
+	}
+	d.off += len(d.data) - len(rest)
+	d.data, d.name = rest, ""
+}
+
+// count tells if n is valid number of bytes to read, recording the
+// failure otherwise.
+func (d *Decoder) count(n int) bool {
+	if n < 0 {
+		d.Fail(ErrRange)
+		return false
+	}
+	return true
+}
+
+// Uint8 reads 8-bit unsigned integer.
+func (d *Decoder) Uint8(x *uint8) {
+	if d.ok() {
+		d.advance(ReadUint8(d.data, x))
+	}
+}
+
+// Uint16 reads 16-bit unsigned integer.
+func (d *Decoder) Uint16(x *uint16) {
+	if d.ok() {
+		d.advance(d.order.ReadUint16(d.data, x))
+	}
+}
+
+// Uint24 reads 24-bit unsigned integer.
+func (d *Decoder) Uint24(x *uint32) {
+	if d.ok() {
+		d.advance(d.order.ReadUint24(d.data, x))
+	}
+}
+
+// Uint32 reads 32-bit unsigned integer.
+func (d *Decoder) Uint32(x *uint32) {
+	if d.ok() {
+		d.advance(d.order.ReadUint32(d.data, x))
+	}
+}
+
+// Uint40 reads 40-bit unsigned integer.
+func (d *Decoder) Uint40(x *uint64) {
+	if d.ok() {
+		d.advance(d.order.ReadUint40(d.data, x))
+	}
+}
+
+// Uint48 reads 48-bit unsigned integer.
+func (d *Decoder) Uint48(x *uint64) {
+	if d.ok() {
+		d.advance(d.order.ReadUint48(d.data, x))
+	}
+}
+
+// Uint56 reads 56-bit unsigned integer.
+func (d *Decoder) Uint56(x *uint64) {
+	if d.ok() {
+		d.advance(d.order.ReadUint56(d.data, x))
+	}
+}
+
+// Uint64 reads 64-bit unsigned integer.
+func (d *Decoder) Uint64(x *uint64) {
+	if d.ok() {
+		d.advance(d.order.ReadUint64(d.data, x))
+	}
+}
+
+// Int8 reads 8-bit signed integer.
+func (d *Decoder) Int8(x *int8) {
+	if d.ok() {
+		d.advance(ReadInt8(d.data, x))
+	}
+}
+
+// Int16 reads 16-bit signed integer.
+func (d *Decoder) Int16(x *int16) {
+	if d.ok() {
+		d.advance(d.order.ReadInt16(d.data, x))
+	}
+}
+
+// Int32 reads 32-bit signed integer.
+func (d *Decoder) Int32(x *int32) {
+	if d.ok() {
+		d.advance(d.order.ReadInt32(d.data, x))
+	}
+}
+
+// Int64 reads 64-bit signed integer.
+func (d *Decoder) Int64(x *int64) {
+	if d.ok() {
+		d.advance(d.order.ReadInt64(d.data, x))
+	}
+}
+
+// Float32 reads 32-bit floating-point number.
+func (d *Decoder) Float32(x *float32) {
+	if d.ok() {
+		d.advance(d.order.ReadFloat32(d.data, x))
+	}
+}
+
+// Float64 reads 64-bit floating-point number.
+func (d *Decoder) Float64(x *float64) {
+	if d.ok() {
+		d.advance(d.order.ReadFloat64(d.data, x))
+	}
+}
+
+// Uvarint reads unsigned LEB128 encoded integer. See ReadUvarint.
+func (d *Decoder) Uvarint(x *uint64) {
+	if d.ok() {
+		d.advance(ReadUvarint(d.data, x))
+	}
+}
+
+// Varint reads zigzag LEB128 encoded integer. See ReadVarint.
+func (d *Decoder) Varint(x *int64) {
+	if d.ok() {
+		d.advance(ReadVarint(d.data, x))
+	}
+}
+
+// QUICVarint reads QUIC variable-length integer. See
+// ReadQUICVarint.
+func (d *Decoder) QUICVarint(x *uint64) {
+	if d.ok() {
+		d.advance(ReadQUICVarint(d.data, x))
+	}
+}
+
+// Bytes reads n bytes into x. x refers to the data. ErrRange is
+// recorded if n is negative.
+func (d *Decoder) Bytes(x *[]byte, n int) {
+	if d.ok() && d.count(n) {
+		d.advance(ReadBytes(d.data, x, n))
+	}
+}
+
+// Copy copies len(x) bytes into x.
+func (d *Decoder) Copy(x []byte) {
+	if !d.ok() {
+		return
+	}
+
+	if len(x) > len(d.data) {
+		// do not copy partially
+		d.Fail(ErrShortData)
+		return
+	}
+	d.advance(CopyBytes(d.data, x))
+}
+
+// Skip skips n bytes. ErrRange is recorded if n is negative.
+func (d *Decoder) Skip(n int) {
+	if d.ok() && d.count(n) {
+		d.advance(SkipBytes(d.data, n))
+	}
+}
+
+// Value decodes s with its PruneFrom method. ErrBadValue is recorded
+// if PruneFrom fails.
+func (d *Decoder) Value(s Serializable) {
+	if !d.ok() {
+		return
+	}
+
+	rest, ok := s.PruneFrom(d.data)
+	if !ok {
+		d.Fail(ErrBadValue)
+		return
+	}
+	d.advance(rest, true)
+}
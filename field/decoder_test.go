@@ -0,0 +1,128 @@
+package field
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	data := []byte{
+		0x01, 0x00, 0x05, 0xff, 0xfe, 0x3f, 0xc0, 0x00, 0x00,
+		0xac, 0x02, 0x7b, 0xbd, 0x01, 0x02, 'a', 'b', 'c', 0xaa,
+	}
+
+	var (
+		version uint8
+		length  uint16
+		delta   int16
+		f       float32
+		x, y    uint64
+		p       Point
+		name    []byte
+		magic   [1]byte
+	)
+
+	d := NewDecoder(data, BigEndian)
+	d.Field("version").Uint8(&version)
+	d.Field("length").Uint16(&length)
+	d.Int16(&delta)
+	d.Float32(&f)
+	d.Uvarint(&x)
+	d.QUICVarint(&y)
+	d.Field("point").Value(&p)
+	d.Bytes(&name, 3)
+	d.Copy(magic[:])
+	assert(t, d.Err() == nil, d.Err())
+	assert(t, d.Offset() == len(data) && d.Len() == 0)
+
+	assert(t, version == 1 && length == 5 && delta == -2 && f == 1.5)
+	assert(t, x == 300 && y == 15293)
+	assert(t, p == Point{1, 2} && string(name) == "abc" && magic[0] == 0xaa)
+
+	d = NewDecoder(data, LittleEndian)
+	d.Skip(1)
+	d.Uint16(&length)
+	assert(t, length == 0x0500 && d.Offset() == 3)
+	assert(t, bytes.Equal(d.Rest(), data[3:]))
+}
+
+func TestDecoderError(t *testing.T) {
+	data := []byte{0x01, 0x00, 0x05, 0xff}
+
+	var version uint8
+	var length, crc uint16
+	var payload []byte
+	d := NewDecoder(data, BigEndian)
+	d.Field("version").Uint8(&version)
+	d.Field("length").Uint16(&length)
+	d.Field("payload").Bytes(&payload, int(length))
+	d.Field("crc").Uint16(&crc)
+	assert(t, version == 1 && length == 5 && payload == nil && crc == 0)
+
+	err := d.Err()
+	assert(t, errors.Is(err, ErrShortData), err)
+
+	var e *Error
+	assert(t, errors.As(err, &e) && e.Offset == 3 && e.Field == "payload", e)
+	assert(t, err.Error() == "field payload: insufficient data at offset 3", err)
+	assert(t, d.Offset() == 3 && d.Len() == 1)
+
+	// array is not copied partially
+	var b [2]byte
+	d = NewDecoder(data[3:], BigEndian)
+	d.Copy(b[:])
+	assert(t, b == [2]byte{} && d.Err() != nil)
+	assert(t, d.Err().Error() == "insufficient data at offset 0", d.Err())
+
+	// validation failure
+	errVersion := fmt.Errorf("unknown version")
+	d = NewDecoder(data, BigEndian)
+	d.Field("version").Uint8(&version)
+	if version != 2 {
+		d.Fail(errVersion)
+	}
+	d.Field("length").Uint16(&length)
+	assert(t, errors.Is(d.Err(), errVersion) && errors.As(d.Err(), &e))
+	assert(t, e.Offset == 1 && e.Field == "", e)
+}
+
+func TestDecoderValue(t *testing.T) {
+	var p Point
+	d := NewDecoder([]byte{0x01, 0x02}, BigEndian)
+	d.Uint8(&p.X)
+	d.Field("point").Value(&p)
+	assert(t, errors.Is(d.Err(), ErrBadValue) && !errors.Is(d.Err(), ErrShortData), d.Err())
+
+	var e *Error
+	assert(t, errors.As(d.Err(), &e) && e.Field == "point" && e.Offset == 1, e)
+	assert(t, d.Offset() == 1 && d.Len() == 1)
+}
+
+func TestDecoderNegative(t *testing.T) {
+	data := []byte{0x00, 0x02, 'a', 'b'}
+
+	var length uint16
+	var payload []byte
+	d := NewDecoder(data, BigEndian)
+	d.Uint16(&length)
+	d.Field("payload").Bytes(&payload, int(length)-3)
+	assert(t, errors.Is(d.Err(), ErrRange), d.Err())
+	assert(t, payload == nil && d.Offset() == 2)
+
+	var e *Error
+	assert(t, errors.As(d.Err(), &e) && e.Field == "payload" && e.Offset == 2, e)
+
+	d = NewDecoder(data, BigEndian)
+	d.Uint16(&length)
+	d.Field("padding").Skip(int(length) - 3)
+	assert(t, errors.Is(d.Err(), ErrRange), d.Err())
+	assert(t, d.Offset() == 2 && d.Len() == 2)
+
+	// zero length is fine
+	d = NewDecoder(data, BigEndian)
+	d.Skip(0)
+	d.Bytes(&payload, 0)
+	assert(t, d.Err() == nil && d.Offset() == 0 && len(payload) == 0)
+}
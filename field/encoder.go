@@ -0,0 +1,196 @@
+package field
+
+// Placeholder is the reserved space in Encoder output which is
+// filled in later, e.g. with the length of data written after it.
+type Placeholder struct {
+	off  int
+	size int
+}
+
+// Encoder appends consecutive fields to the data. Unlike append
+// style functions it allows to reserve space for the fields which
+// values are not known in advance and patch them later:
+//
+//	e := field.NewEncoder(nil, field.BigEndian)
+//	e.Uint8(version)
+//	length := e.Reserve(2)
+//	e.Bytes(payload)
+//	e.PatchLength(length)
+//	if err := e.Err(); err != nil {
+//		return err
+//	}
+//	data := e.Data()
+//
+// The first failure is recorded and returned by Err.
+type Encoder struct {
+	data  []byte
+	order Endianness
+	err   error
+}
+
+// NewEncoder returns the Encoder which appends to data. Multi-byte
+// integers and floats are written with specified endianness.
+func NewEncoder(data []byte, order Endianness) *Encoder {
+	return &Encoder{data: data, order: order}
+}
+
+// Data returns the resulting slice.
+func (e *Encoder) Data() []byte {
+	return e.data
+}
+
+// Len returns the length of the resulting slice.
+func (e *Encoder) Len() int {
+	return len(e.data)
+}
+
+// Err returns the first failure as *Error, or nil.
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+// fail records err at offset unless another failure is already
+// recorded.
+func (e *Encoder) fail(off int, err error) {
+	if e.err == nil {
+		e.err = &Error{Offset: off, Err: err}
+	}
+}
+
+// Uint8 writes 8-bit unsigned integer.
+func (e *Encoder) Uint8(x uint8) {
+	e.data = WriteUint8(e.data, x)
+}
+
+// Uint16 writes 16-bit unsigned integer.
+func (e *Encoder) Uint16(x uint16) {
+	e.data = e.order.WriteUint16(e.data, x)
+}
+
+// Uint24 writes 24 least significant bits of x.
+func (e *Encoder) Uint24(x uint32) {
+	e.data = e.order.WriteUint24(e.data, x)
+}
+
+// Uint32 writes 32-bit unsigned integer.
+func (e *Encoder) Uint32(x uint32) {
+	e.data = e.order.WriteUint32(e.data, x)
+}
+
+// Uint40 writes 40 least significant bits of x.
+func (e *Encoder) Uint40(x uint64) {
+	e.data = e.order.WriteUint40(e.data, x)
+}
+
+// Uint48 writes 48 least significant bits of x.
+func (e *Encoder) Uint48(x uint64) {
+	e.data = e.order.WriteUint48(e.data, x)
+}
+
+// Uint56 writes 56 least significant bits of x.
+func (e *Encoder) Uint56(x uint64) {
+	e.data = e.order.WriteUint56(e.data, x)
+}
+
+// Uint64 writes 64-bit unsigned integer.
+func (e *Encoder) Uint64(x uint64) {
+	e.data = e.order.WriteUint64(e.data, x)
+}
+
+// Int8 writes 8-bit signed integer.
+func (e *Encoder) Int8(x int8) {
+	e.data = WriteInt8(e.data, x)
+}
+
+// Int16 writes 16-bit signed integer.
+func (e *Encoder) Int16(x int16) {
+	e.data = e.order.WriteInt16(e.data, x)
+}
+
+// Int32 writes 32-bit signed integer.
+func (e *Encoder) Int32(x int32) {
+	e.data = e.order.WriteInt32(e.data, x)
+}
+
+// Int64 writes 64-bit signed integer.
+func (e *Encoder) Int64(x int64) {
+	e.data = e.order.WriteInt64(e.data, x)
+}
+
+// Float32 writes 32-bit floating-point number.
+func (e *Encoder) Float32(x float32) {
+	e.data = e.order.WriteFloat32(e.data, x)
+}
+
+// Float64 writes 64-bit floating-point number.
+func (e *Encoder) Float64(x float64) {
+	e.data = e.order.WriteFloat64(e.data, x)
+}
+
+// Uvarint writes unsigned LEB128 encoded integer.
+func (e *Encoder) Uvarint(x uint64) {
+	e.data = WriteUvarint(e.data, x)
+}
+
+// Varint writes zigzag LEB128 encoded integer.
+func (e *Encoder) Varint(x int64) {
+	e.data = WriteVarint(e.data, x)
+}
+
+// QUICVarint writes QUIC variable-length integer. ErrRange is
+// recorded if x exceeds MaxQUICVarint.
+func (e *Encoder) QUICVarint(x uint64) {
+	var ok bool
+	if e.data, ok = WriteQUICVarint(e.data, x); !ok {
+		e.fail(len(e.data), ErrRange)
+	}
+}
+
+// Bytes writes b as is.
+func (e *Encoder) Bytes(b []byte) {
+	e.data = append(e.data, b...)
+}
+
+// Zeros writes n zero bytes.
+func (e *Encoder) Zeros(n int) {
+	for ; n > 0; n-- {
+		e.data = append(e.data, 0)
+	}
+}
+
+// Value encodes s with its AppendTo method.
+func (e *Encoder) Value(s Serializable) {
+	e.data = s.AppendTo(e.data)
+}
+
+// Reserve writes size zero bytes to be patched later and returns
+// the placeholder. The size should be within [1, 8].
+func (e *Encoder) Reserve(size int) Placeholder {
+	if size < 1 || size > 8 {
+		panic("invalid placeholder size")
+	}
+
+	p := Placeholder{off: len(e.data), size: size}
+	e.Zeros(size)
+	return p
+}
+
+// Patch writes unsigned integer x into the placeholder p. ErrRange
+// is recorded if x does not fit into the placeholder or p is not
+// reserved within the output, e.g. it is the zero value or is
+// returned by another Encoder.
+func (e *Encoder) Patch(p Placeholder, x uint64) {
+	if p.size < 1 || p.off+p.size > len(e.data) || x > mask(8*p.size) {
+		e.fail(p.off, ErrRange)
+		return
+	}
+
+	var buf [8]byte
+	copy(e.data[p.off:], writeInt(buf[:0], x, p.size, e.order))
+}
+
+// PatchLength writes the number of bytes written after the
+// placeholder p into it.
+func (e *Encoder) PatchLength(p Placeholder) {
+	e.Patch(p, uint64(len(e.data)-p.off-p.size))
+}
@@ -0,0 +1,91 @@
+package field
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	p := Point{1, 2}
+	e := NewEncoder([]byte{0xaa}, BigEndian)
+	e.Uint8(1)
+	length := e.Reserve(2)
+	e.Int16(-2)
+	e.Float32(1.5)
+	e.Uvarint(300)
+	e.QUICVarint(15293)
+	e.Value(&p)
+	e.Bytes([]byte("abc"))
+	e.PatchLength(length)
+	e.Zeros(1)
+	assert(t, e.Err() == nil, e.Err())
+
+	want := []byte{
+		0xaa, 0x01, 0x00, 0x0f, 0xff, 0xfe, 0x3f, 0xc0, 0x00, 0x00,
+		0xac, 0x02, 0x7b, 0xbd, 0x01, 0x02, 'a', 'b', 'c', 0x00,
+	}
+	assert(t, bytes.Equal(e.Data(), want), e.Data())
+	assert(t, e.Len() == len(want))
+
+	e = NewEncoder(nil, LittleEndian)
+	tag := e.Reserve(3)
+	e.Uint16(0x0102)
+	e.Patch(tag, 0x112233)
+	assert(t, bytes.Equal(e.Data(), []byte{0x33, 0x22, 0x11, 0x02, 0x01}), e.Data())
+
+	// value does not fit
+	e.Patch(tag, 1<<24)
+	e.QUICVarint(MaxQUICVarint + 1)
+	var err *Error
+	assert(t, errors.Is(e.Err(), ErrRange) && errors.As(e.Err(), &err))
+	assert(t, err.Offset == 0, err)
+	assert(t, bytes.Equal(e.Data(), []byte{0x33, 0x22, 0x11, 0x02, 0x01}), e.Data())
+}
+
+func TestEncoderPatch(t *testing.T) {
+	e := NewEncoder(nil, BigEndian)
+	length := e.Reserve(1)
+	e.Zeros(255)
+	e.PatchLength(length)
+	assert(t, e.Err() == nil && e.Data()[0] == 0xff, e.Err())
+
+	// length does not fit
+	e.Zeros(1)
+	e.PatchLength(length)
+	assert(t, errors.Is(e.Err(), ErrRange) && e.Data()[0] == 0xff, e.Err())
+}
+
+func TestEncoderPlaceholder(t *testing.T) {
+	other := NewEncoder(nil, BigEndian)
+	other.Zeros(4)
+	foreign := other.Reserve(2)
+
+	for _, p := range []Placeholder{{}, foreign} {
+		e := NewEncoder(nil, BigEndian)
+		e.Uint16(0x0102)
+		e.Patch(p, 1)
+		assert(t, errors.Is(e.Err(), ErrRange), p, e.Err())
+		assert(t, bytes.Equal(e.Data(), []byte{0x01, 0x02}), e.Data())
+
+		e = NewEncoder(nil, BigEndian)
+		e.PatchLength(p)
+		assert(t, errors.Is(e.Err(), ErrRange), p, e.Err())
+	}
+}
+
+func TestEncoderReserveSize(t *testing.T) {
+	for _, size := range []int{0, 9, -1} {
+		func() {
+			defer func() {
+				assert(t, recover() != nil, size)
+			}()
+			NewEncoder(nil, BigEndian).Reserve(size)
+		}()
+	}
+
+	e := NewEncoder(nil, BigEndian)
+	p := e.Reserve(8)
+	e.Patch(p, 1<<63)
+	assert(t, e.Err() == nil && e.Data()[0] == 0x80, e.Data())
+}
@@ -0,0 +1,632 @@
+package field
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Error values returned by Marshal and Unmarshal.
+var (
+	// ErrShortData returned if data is insufficient to decode value.
+	ErrShortData = fmt.Errorf("insufficient data")
+	// ErrRange returned if value does not fit into the field.
+	ErrRange = fmt.Errorf("value out of range")
+	// ErrUnsupported returned if value type is not supported.
+	ErrUnsupported = fmt.Errorf("unsupported type")
+	// ErrTag returned if the field tag is invalid.
+	ErrTag = fmt.Errorf("invalid field tag")
+)
+
+var serializableType = reflect.TypeOf((*Serializable)(nil)).Elem()
+
+// options of the struct field parsed from its tag.
+type options struct {
+	// endianness of integer values
+	order Endianness
+	// size of integer values in bytes; 0 means natural size
+	size int
+	// index of the field holding the number of elements; -1 if none
+	lenField int
+	// size of the length prefix in bytes; 0 if none
+	prefix int
+	// length of encoded field is padded to a multiple of pad
+	pad int
+	// index of the slice field which length this field holds; -1 if
+	// none
+	lenOf int
+	// the struct is encoded with its own Serializable methods
+	self bool
+}
+
+var defaultOptions = options{order: BigEndian, lenField: -1, lenOf: -1}
+
+// structField is the struct field to marshal.
+type structField struct {
+	options
+	index int
+	name  string
+	blank bool
+}
+
+var structCache sync.Map // map[reflect.Type][]structField
+
+// serializable tells if v is encoded with its Serializable methods.
+// The struct embedding Serializable gets the methods promoted so it
+// is encoded field by field instead, unless the embedded field is
+// tagged with "self".
+func serializable(v reflect.Value) bool {
+	if !v.CanAddr() || !v.Addr().Type().Implements(serializableType) {
+		return false
+	}
+
+	t := v.Type()
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && reflect.PtrTo(f.Type).Implements(serializableType) {
+			return hasOption(f.Tag.Get("field"), "self")
+		}
+	}
+	return true
+}
+
+// hasOption tells if the field tag contains the option.
+func hasOption(tag, option string) bool {
+	for _, s := range strings.Split(tag, ",") {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+func intSize(s string) int {
+	switch s {
+	case "u8":
+		return 1
+	case "u16":
+		return 2
+	case "u24":
+		return 3
+	case "u32":
+		return 4
+	case "u64":
+		return 8
+	}
+	return 0
+}
+
+// parseTag parses the field tag.
+func parseTag(tag string, fieldIndex map[string]int) (opts options, err error) {
+	opts = defaultOptions
+	for _, s := range strings.Split(tag, ",") {
+		key, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			key, value = s[:i], s[i+1:]
+		}
+
+		switch key {
+		case "":
+		case "be":
+			opts.order = BigEndian
+		case "le":
+			opts.order = LittleEndian
+		case "u8", "u16", "u24", "u32", "u64":
+			opts.size = intSize(key)
+		case "len":
+			var ok bool
+			if opts.lenField, ok = fieldIndex[value]; !ok {
+				return opts, fmt.Errorf("%w: unknown length field %q", ErrTag, value)
+			}
+		case "prefix":
+			if opts.prefix = intSize(value); opts.prefix == 0 || opts.prefix > 4 {
+				return opts, fmt.Errorf("%w: %q", ErrTag, s)
+			}
+		case "self":
+			opts.self = true
+		case "pad":
+			if opts.pad, err = strconv.Atoi(value); err != nil || opts.pad <= 0 {
+				return opts, fmt.Errorf("%w: %q", ErrTag, s)
+			}
+		default:
+			return opts, fmt.Errorf("%w: %q", ErrTag, s)
+		}
+	}
+	return opts, nil
+}
+
+// structFields returns the fields of struct type t to marshal.
+func structFields(t reflect.Type) ([]structField, error) {
+	if fields, ok := structCache.Load(t); ok {
+		return fields.([]structField), nil
+	}
+
+	fieldIndex := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		fieldIndex[t.Field(i).Name] = i
+	}
+
+	var fields []structField
+	pos := make(map[int]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("field")
+		if tag == "-" || (f.PkgPath != "" && f.Name != "_") {
+			// skipped or unexported
+			continue
+		}
+
+		opts, err := parseTag(tag, fieldIndex)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		if opts.self {
+			// the struct is not encoded field by field then
+			return nil, fmt.Errorf("field %s: %w: self applies to embedded Serializable", f.Name, ErrTag)
+		}
+
+		if opts.lenField >= 0 {
+			j, ok := pos[opts.lenField]
+			if !ok {
+				return nil, fmt.Errorf("field %s: %w: length field should precede", f.Name, ErrTag)
+			}
+			fields[j].lenOf = i
+		}
+
+		pos[i] = len(fields)
+		fields = append(fields, structField{
+			options: opts,
+			index:   i,
+			name:    f.Name,
+			blank:   f.Name == "_",
+		})
+	}
+
+	structCache.Store(t, fields)
+	return fields, nil
+}
+
+// Marshal appends binary representation of v to data and returns
+// the resulting slice. The value v is usually a struct or a pointer
+// to struct.
+//
+// Struct fields are encoded in the order of declaration. Encoding of
+// a field may be tuned with the comma separated options in the
+// "field" tag:
+//
+//	be, le          - endianness of integers, big endian by default
+//	u8, u16, u24,
+//	u32, u64        - size of integers, natural size by default;
+//	                  int, uint and uintptr take 8 bytes
+//	len=Count       - the number of elements of slice or string is
+//	                  stored in the preceding field Count
+//	prefix=u8, u16,
+//	u24, u32        - slice or string is prefixed with the number of
+//	                  its elements
+//	pad=4           - encoded field is padded with zeros to the
+//	                  multiple of 4 bytes
+//	self            - the struct embedding Serializable in this
+//	                  field is encoded with its own methods
+//
+// The field tagged with "-" and unexported fields are skipped, blank
+// fields are encoded as zeros. Integer options apply to the elements
+// of arrays and slices. Slice or string with neither len nor prefix
+// option occupies all the remaining data, so it should be the last
+// field.
+//
+// Supported types are booleans, integers, floats, strings, arrays,
+// slices and structs of them, and the types which pointer implements
+// Serializable interface. Booleans are encoded in one byte, floats
+// are encoded as integers of IEEE 754 binary representation. The
+// struct embedding Serializable is encoded field by field, so the
+// methods promoted from the embedded field are not used for the
+// whole struct. If the struct declares its own AppendTo and PruneFrom
+// methods, tag the embedded field with "self" to use them instead.
+func Marshal(data []byte, v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	} else if rv.IsValid() {
+		// make value addressable to use Serializable
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		rv = p.Elem()
+	}
+
+	if !rv.IsValid() {
+		return data, fmt.Errorf("%w: %T", ErrUnsupported, v)
+	}
+	return marshal(data, rv, &defaultOptions)
+}
+
+// writeInt appends integer x of size bytes.
+func writeInt(data []byte, x uint64, size int, order Endianness) []byte {
+	switch size {
+	case 1:
+		return WriteUint8(data, uint8(x))
+	case 2:
+		return order.WriteUint16(data, uint16(x))
+	case 3:
+		return order.WriteUint24(data, uint32(x))
+	case 4:
+		return order.WriteUint32(data, uint32(x))
+	case 5:
+		return order.WriteUint40(data, x)
+	case 6:
+		return order.WriteUint48(data, x)
+	case 7:
+		return order.WriteUint56(data, x)
+	}
+	return order.WriteUint64(data, x)
+}
+
+// readInt reads integer of size bytes.
+func readInt(data []byte, x *uint64, size int, order Endianness) ([]byte, bool) {
+	var ok bool
+	switch size {
+	case 1:
+		var y uint8
+		data, ok = ReadUint8(data, &y)
+		*x = uint64(y)
+	case 2:
+		var y uint16
+		data, ok = order.ReadUint16(data, &y)
+		*x = uint64(y)
+	case 3:
+		var y uint32
+		data, ok = order.ReadUint24(data, &y)
+		*x = uint64(y)
+	case 4:
+		var y uint32
+		data, ok = order.ReadUint32(data, &y)
+		*x = uint64(y)
+	case 5:
+		data, ok = order.ReadUint40(data, x)
+	case 6:
+		data, ok = order.ReadUint48(data, x)
+	case 7:
+		data, ok = order.ReadUint56(data, x)
+	default:
+		data, ok = order.ReadUint64(data, x)
+	}
+	return data, ok
+}
+
+// sizeOf returns the size of integer v in bytes. The size of int,
+// uint and uintptr does not depend on the platform.
+func sizeOf(v reflect.Value, opts *options) int {
+	if opts.size != 0 {
+		return opts.size
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return 8
+	}
+	return int(v.Type().Size())
+}
+
+// length returns the number of elements of slice or string.
+func length(v reflect.Value) (int, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.String:
+		return v.Len(), nil
+	}
+	return 0, fmt.Errorf("%w: length of %v", ErrUnsupported, v.Type())
+}
+
+func marshal(data []byte, v reflect.Value, opts *options) ([]byte, error) {
+	if serializable(v) {
+		return v.Addr().Interface().(Serializable).AppendTo(data), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		var x uint64
+		if v.Bool() {
+			x = 1
+		}
+		return writeInt(data, x, 1, opts.order), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		size := sizeOf(v, opts)
+		x := v.Uint()
+		if size < 8 && x>>(8*uint(size)) != 0 {
+			return data, ErrRange
+		}
+		return writeInt(data, x, size, opts.order), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size := sizeOf(v, opts)
+		x := v.Int()
+		if shift := 64 - 8*uint(size); x<<shift>>shift != x {
+			return data, ErrRange
+		}
+		return writeInt(data, uint64(x), size, opts.order), nil
+
+	case reflect.Float32:
+		return writeInt(data, uint64(math.Float32bits(float32(v.Float()))), 4, opts.order), nil
+
+	case reflect.Float64:
+		return writeInt(data, math.Float64bits(v.Float()), 8, opts.order), nil
+
+	case reflect.String:
+		return append(data, v.String()...), nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 && opts.size <= 1 {
+			return append(data, v.Bytes()...), nil
+		}
+		fallthrough
+
+	case reflect.Array:
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if data, err = marshal(data, v.Index(i), opts); err != nil {
+				return data, err
+			}
+		}
+		return data, nil
+
+	case reflect.Struct:
+		return marshalStruct(data, v)
+	}
+
+	return data, fmt.Errorf("%w: %v", ErrUnsupported, v.Type())
+}
+
+func marshalStruct(data []byte, v reflect.Value) ([]byte, error) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return data, err
+	}
+
+	for i := range fields {
+		f := &fields[i]
+		fv := v.Field(f.index)
+		if f.blank {
+			fv = reflect.New(fv.Type()).Elem()
+		}
+
+		start := len(data)
+		switch {
+		case f.lenOf >= 0:
+			// the number of elements of the slice
+			n, err := length(v.Field(f.lenOf))
+			if err != nil {
+				return data, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			nv := reflect.New(fv.Type()).Elem()
+			nv.Set(reflect.ValueOf(n).Convert(fv.Type()))
+			fv = nv
+		case f.prefix > 0:
+			n, err := length(fv)
+			if err != nil {
+				return data, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			if uint64(n)>>(8*uint(f.prefix)) != 0 {
+				return data, fmt.Errorf("field %s: %w", f.name, ErrRange)
+			}
+			data = writeInt(data, uint64(n), f.prefix, f.order)
+		}
+
+		if data, err = marshal(data, fv, &f.options); err != nil {
+			return data, fmt.Errorf("field %s: %w", f.name, err)
+		}
+
+		if f.pad > 0 {
+			for (len(data)-start)%f.pad != 0 {
+				data = append(data, 0)
+			}
+		}
+	}
+	return data, nil
+}
+
+// Unmarshal decodes binary representation from the top of data into
+// v which should be a pointer. It returns remaining data and
+// possible error. See Marshal for the encoding rules.
+func Unmarshal(data []byte, v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return data, fmt.Errorf("%w: non-pointer %T", ErrUnsupported, v)
+	}
+
+	return unmarshal(data, rv.Elem(), &defaultOptions, -1)
+}
+
+// unmarshal decodes v. If n is not negative it is the number of
+// elements of slice or string, otherwise they occupy the rest of
+// data.
+func unmarshal(data []byte, v reflect.Value, opts *options, n int) ([]byte, error) {
+	if serializable(v) {
+		rest, ok := v.Addr().Interface().(Serializable).PruneFrom(data)
+		if !ok {
+			return data, ErrShortData
+		}
+		return rest, nil
+	}
+
+	var x uint64
+	var ok bool
+	switch v.Kind() {
+	case reflect.Bool:
+		if data, ok = readInt(data, &x, 1, opts.order); ok {
+			v.SetBool(x != 0)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		size := sizeOf(v, opts)
+		if data, ok = readInt(data, &x, size, opts.order); ok {
+			if v.OverflowUint(x) {
+				return data, ErrRange
+			}
+			v.SetUint(x)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size := sizeOf(v, opts)
+		if data, ok = readInt(data, &x, size, opts.order); ok {
+			// sign extension
+			shift := 64 - 8*uint(size)
+			y := int64(x<<shift) >> shift
+			if v.OverflowInt(y) {
+				return data, ErrRange
+			}
+			v.SetInt(y)
+		}
+
+	case reflect.Float32:
+		if data, ok = readInt(data, &x, 4, opts.order); ok {
+			v.SetFloat(float64(math.Float32frombits(uint32(x))))
+		}
+
+	case reflect.Float64:
+		if data, ok = readInt(data, &x, 8, opts.order); ok {
+			v.SetFloat(math.Float64frombits(x))
+		}
+
+	case reflect.String:
+		if n < 0 {
+			n = len(data)
+		}
+		var b []byte
+		if data, ok = ReadBytes(data, &b, n); ok {
+			v.SetString(string(b))
+		}
+
+	case reflect.Slice:
+		return unmarshalSlice(data, v, opts, n)
+
+	case reflect.Array:
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			if data, err = unmarshal(data, v.Index(i), opts, -1); err != nil {
+				return data, err
+			}
+		}
+		return data, nil
+
+	case reflect.Struct:
+		return unmarshalStruct(data, v)
+
+	default:
+		return data, fmt.Errorf("%w: %v", ErrUnsupported, v.Type())
+	}
+
+	if !ok {
+		return data, ErrShortData
+	}
+	return data, nil
+}
+
+func unmarshalSlice(data []byte, v reflect.Value, opts *options, n int) ([]byte, error) {
+	t := v.Type()
+	if t.Elem().Kind() == reflect.Uint8 && opts.size <= 1 {
+		if n < 0 {
+			n = len(data)
+		}
+		var b []byte
+		data, ok := ReadBytes(data, &b, n)
+		if !ok {
+			return data, ErrShortData
+		}
+		v.SetBytes(append([]byte(nil), b...))
+		return data, nil
+	}
+
+	if n < 0 {
+		// elements occupy the rest of data
+		s := reflect.MakeSlice(t, 0, 0)
+		for len(data) > 0 {
+			e := reflect.New(t.Elem()).Elem()
+			rest, err := unmarshal(data, e, opts, -1)
+			if err != nil {
+				return rest, err
+			}
+			if len(rest) == len(data) {
+				// the loop would never end
+				return data, fmt.Errorf("%w: %v occupies no data", ErrUnsupported, t.Elem())
+			}
+			data = rest
+			s = reflect.Append(s, e)
+		}
+		v.Set(s)
+		return data, nil
+	}
+
+	if n > len(data) {
+		// every element occupies at least one byte
+		return data, ErrShortData
+	}
+
+	s := reflect.MakeSlice(t, n, n)
+	for i := 0; i < n; i++ {
+		var err error
+		if data, err = unmarshal(data, s.Index(i), opts, -1); err != nil {
+			return data, err
+		}
+	}
+	v.Set(s)
+	return data, nil
+}
+
+func unmarshalStruct(data []byte, v reflect.Value) ([]byte, error) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return data, err
+	}
+
+	for i := range fields {
+		f := &fields[i]
+		fv := v.Field(f.index)
+		if f.blank {
+			fv = reflect.New(fv.Type()).Elem()
+		}
+
+		start := len(data)
+		n := -1
+		switch {
+		case f.lenField >= 0:
+			lv := v.Field(f.lenField)
+			switch lv.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				n = int(lv.Uint())
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n = int(lv.Int())
+			}
+			if n < 0 {
+				return data, fmt.Errorf("field %s: %w", f.name, ErrRange)
+			}
+		case f.prefix > 0:
+			var x uint64
+			var ok bool
+			if data, ok = readInt(data, &x, f.prefix, f.order); !ok {
+				return data, fmt.Errorf("field %s: %w", f.name, ErrShortData)
+			}
+			n = int(x)
+		}
+
+		if data, err = unmarshal(data, fv, &f.options, n); err != nil {
+			return data, fmt.Errorf("field %s: %w", f.name, err)
+		}
+
+		if f.pad > 0 {
+			var ok bool
+			k := (start - len(data)) % f.pad
+			if k != 0 {
+				if data, ok = SkipBytes(data, f.pad-k); !ok {
+					return data, fmt.Errorf("field %s: %w", f.name, ErrShortData)
+				}
+			}
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,270 @@
+package field
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Point implements Serializable.
+type Point struct {
+	X, Y uint8
+}
+
+func (p *Point) AppendTo(data []byte) []byte {
+	return append(data, p.X, p.Y)
+}
+
+func (p *Point) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < 2 {
+		return nil, false
+	}
+	p.X, p.Y = data[0], data[1]
+	return data[2:], true
+}
+
+type Header struct {
+	Version uint8
+	Flags   uint16 `field:"le"`
+	Length  uint32 `field:"u24"`
+}
+
+type message struct {
+	Header
+	Point
+	Count  uint8
+	Items  []uint16 `field:"len=Count"`
+	Name   string   `field:"prefix=u8,pad=4"`
+	Offset int32    `field:"le,u24"`
+	Fixed  [2]uint32
+	_      [2]byte
+	OK     bool
+	Ratio  float32
+	Skip   int `field:"-"`
+	hidden int
+	Tail   []byte
+}
+
+func TestMarshal(t *testing.T) {
+	m := message{
+		Header: Header{1, 0x0203, 0x040506},
+		Point:  Point{7, 8},
+		Items:  []uint16{0x1122, 0x3344},
+		Name:   "abcde",
+		Offset: -2,
+		Fixed:  [2]uint32{9, 10},
+		OK:     true,
+		Ratio:  1.5,
+		Skip:   100,
+		hidden: 200,
+		Tail:   []byte{0xaa, 0xbb},
+	}
+
+	expected := []byte{
+		0x01, 0x03, 0x02, 0x04, 0x05, 0x06, // Header
+		0x07, 0x08, // Point
+		0x02, 0x11, 0x22, 0x33, 0x44, // Count, Items
+		0x05, 'a', 'b', 'c', 'd', 'e', 0x00, 0x00, // Name
+		0xfe, 0xff, 0xff, // Offset
+		0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x0a, // Fixed
+		0x00, 0x00, // blank
+		0x01,                   // OK
+		0x3f, 0xc0, 0x00, 0x00, // Ratio
+		0xaa, 0xbb, // Tail
+	}
+
+	data, err := Marshal([]byte{0xff}, m)
+	assert(t, err == nil, err)
+	assert(t, bytes.Equal(data[1:], expected), data)
+
+	data2, err := Marshal(nil, &m)
+	assert(t, err == nil && bytes.Equal(data2, data[1:]), err)
+
+	var m2 message
+	rest, err := Unmarshal(data[1:], &m2)
+	assert(t, err == nil && len(rest) == 0, err)
+
+	m.Count, m.Skip, m.hidden = 2, 0, 0
+	assert(t, reflect.DeepEqual(m, m2), m2)
+
+	for i := range expected {
+		_, err = Unmarshal(expected[:i], &m2)
+		if i < len(expected)-2 {
+			assert(t, errors.Is(err, ErrShortData), i, err)
+		}
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	_, err := Marshal(nil, struct{ X uint32 }{1})
+	assert(t, err == nil)
+
+	_, err = Marshal(nil, struct {
+		X uint32 `field:"u16"`
+	}{0x10000})
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Marshal(nil, struct {
+		X int16 `field:"u8"`
+	}{-129})
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Marshal(nil, struct{ X map[int]int }{})
+	assert(t, errors.Is(err, ErrUnsupported), err)
+
+	_, err = Marshal(nil, struct {
+		X []byte `field:"len=N"`
+		N int
+	}{})
+	assert(t, errors.Is(err, ErrTag), err)
+
+	_, err = Marshal(nil, struct {
+		X uint8 `field:"big"`
+	}{})
+	assert(t, errors.Is(err, ErrTag), err)
+
+	_, err = Marshal(nil, nil)
+	assert(t, errors.Is(err, ErrUnsupported), err)
+
+	var x struct{ X uint8 }
+	_, err = Unmarshal(nil, x)
+	assert(t, errors.Is(err, ErrUnsupported), err)
+
+	var y struct {
+		X int8 `field:"u16"`
+	}
+	_, err = Unmarshal([]byte{0x01, 0x00}, &y)
+	assert(t, errors.Is(err, ErrRange), err)
+
+	_, err = Unmarshal([]byte{0xff, 0xff}, &y)
+	assert(t, err == nil && y.X == -1, err)
+}
+
+func TestUnmarshalRest(t *testing.T) {
+	var v struct {
+		N     uint8
+		Items []Point
+	}
+
+	rest, err := Unmarshal([]byte{3, 1, 2, 3, 4}, &v)
+	assert(t, err == nil && len(rest) == 0, err)
+	assert(t, v.N == 3 && len(v.Items) == 2 && v.Items[1] == Point{3, 4}, v)
+
+	_, err = Unmarshal([]byte{3, 1, 2, 3}, &v)
+	assert(t, errors.Is(err, ErrShortData), err)
+}
+
+// nothing implements Serializable consuming no data.
+type nothing struct{}
+
+func (*nothing) AppendTo(data []byte) []byte { return data }
+
+func (*nothing) PruneFrom(data []byte) ([]byte, bool) { return data, true }
+
+func TestUnmarshalNoProgress(t *testing.T) {
+	var v struct {
+		Items []nothing
+	}
+	_, err := Unmarshal([]byte{1, 2}, &v)
+	assert(t, errors.Is(err, ErrUnsupported), err)
+
+	var w struct {
+		Items []struct{}
+	}
+	_, err = Unmarshal([]byte{1, 2}, &w)
+	assert(t, errors.Is(err, ErrUnsupported), err)
+}
+
+// tagged embeds Serializable and declares its own methods.
+type tagged struct {
+	Point `field:"self"`
+	Tag   uint8
+}
+
+func (p *tagged) AppendTo(data []byte) []byte {
+	return append(data, p.Tag, p.X, p.Y)
+}
+
+func (p *tagged) PruneFrom(data []byte) ([]byte, bool) {
+	if len(data) < 3 {
+		return nil, false
+	}
+	p.Tag, p.X, p.Y = data[0], data[1], data[2]
+	return data[3:], true
+}
+
+// untagged embeds Serializable and declares its own methods which are
+// not used.
+type untagged struct {
+	Point
+	Tag uint8
+}
+
+func (p *untagged) AppendTo(data []byte) []byte {
+	return (*tagged)(p).AppendTo(data)
+}
+
+func (p *untagged) PruneFrom(data []byte) ([]byte, bool) {
+	return (*tagged)(p).PruneFrom(data)
+}
+
+func TestMarshalDeclaredMethods(t *testing.T) {
+	v := tagged{Point{1, 2}, 3}
+	data, err := Marshal(nil, &v)
+	assert(t, err == nil && bytes.Equal(data, []byte{3, 1, 2}), data)
+
+	var w struct {
+		T tagged
+	}
+	rest, err := Unmarshal([]byte{4, 5, 6, 7}, &w)
+	assert(t, err == nil && bytes.Equal(rest, []byte{7}), err)
+	assert(t, w.T == tagged{Point{5, 6}, 4}, w)
+
+	u := untagged{Point{1, 2}, 3}
+	data, err = Marshal(nil, &u)
+	assert(t, err == nil && bytes.Equal(data, []byte{1, 2, 3}), data)
+
+	rest, err = Unmarshal([]byte{4, 5, 6, 7}, &u)
+	assert(t, err == nil && bytes.Equal(rest, []byte{7}), err)
+	assert(t, u.X == 4 && u.Y == 5 && u.Tag == 6, u)
+
+	var x struct {
+		P Point `field:"self"`
+	}
+	_, err = Marshal(nil, &x)
+	assert(t, errors.Is(err, ErrTag), err)
+}
+
+func TestMarshalPlatformInt(t *testing.T) {
+	v := struct {
+		A int
+		B uint
+		C uintptr
+		D int `field:"u16"`
+	}{-1, 2, 3, 4}
+	data, err := Marshal(nil, &v)
+	assert(t, err == nil && len(data) == 26, data)
+	assert(t, bytes.Equal(data[:8], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}), data)
+	assert(t, bytes.Equal(data[8:16], []byte{0, 0, 0, 0, 0, 0, 0, 2}), data)
+	assert(t, bytes.Equal(data[16:24], []byte{0, 0, 0, 0, 0, 0, 0, 3}), data)
+
+	w := v
+	w.A, w.B, w.C, w.D = 0, 0, 0, 0
+	rest, err := Unmarshal(data, &w)
+	assert(t, err == nil && len(rest) == 0 && w == v, err, w)
+}
+
+func TestIntSizes(t *testing.T) {
+	for _, order := range []Endianness{BigEndian, LittleEndian} {
+		for size := 1; size <= 8; size++ {
+			x := uint64(0x0102030405060708) & mask(8*size)
+			data := writeInt(nil, x, size, order)
+			assert(t, len(data) == size, size, data)
+
+			var y uint64
+			rest, ok := readInt(data, &y, size, order)
+			assert(t, ok && len(rest) == 0 && y == x, size, y)
+		}
+	}
+}
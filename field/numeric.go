@@ -0,0 +1,381 @@
+package field
+
+import (
+	"math"
+)
+
+// WriteInt8 writes a Int8 value to specified slice and returns the
+// resulting slice.
+func WriteInt8(data []byte, x int8) []byte {
+	return append(data, uint8(x))
+}
+
+// ReadInt8 reads a Int8 value from specified slice and returns the
+// resulting slice and boolean flag telling if it was a success.
+func ReadInt8(data []byte, x *int8) ([]byte, bool) {
+	if len(data) >= 1 {
+		*x = int8(data[0])
+		return data[1:], true
+	}
+
+	return nil, false
+}
+
+// writeUintBE appends n least significant bytes of x in big endian.
+func writeUintBE(data []byte, x uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		data = append(data, byte(x>>(8*uint(i))))
+	}
+	return data
+}
+
+// writeUintLE appends n least significant bytes of x in little
+// endian.
+func writeUintLE(data []byte, x uint64, n int) []byte {
+	for i := 0; i < n; i++ {
+		data = append(data, byte(x>>(8*uint(i))))
+	}
+	return data
+}
+
+// readUintBE reads n bytes big endian integer.
+func readUintBE(data []byte, x *uint64, n int) ([]byte, bool) {
+	if len(data) < n {
+		return nil, false
+	}
+
+	*x = 0
+	for i := 0; i < n; i++ {
+		*x = *x<<8 | uint64(data[i])
+	}
+	return data[n:], true
+}
+
+// readUintLE reads n bytes little endian integer.
+func readUintLE(data []byte, x *uint64, n int) ([]byte, bool) {
+	if len(data) < n {
+		return nil, false
+	}
+
+	*x = 0
+	for i := n - 1; i >= 0; i-- {
+		*x = *x<<8 | uint64(data[i])
+	}
+	return data[n:], true
+}
+
+// WriteUint40 writes 40 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as big endian.
+func (bigEndian) WriteUint40(data []byte, x uint64) []byte {
+	return writeUintBE(data, x, 5)
+}
+
+// WriteUint48 writes 48 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as big endian.
+func (bigEndian) WriteUint48(data []byte, x uint64) []byte {
+	return writeUintBE(data, x, 6)
+}
+
+// WriteUint56 writes 56 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as big endian.
+func (bigEndian) WriteUint56(data []byte, x uint64) []byte {
+	return writeUintBE(data, x, 7)
+}
+
+// ReadUint40 reads a 40-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (bigEndian) ReadUint40(data []byte, x *uint64) ([]byte, bool) {
+	return readUintBE(data, x, 5)
+}
+
+// ReadUint48 reads a 48-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (bigEndian) ReadUint48(data []byte, x *uint64) ([]byte, bool) {
+	return readUintBE(data, x, 6)
+}
+
+// ReadUint56 reads a 56-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (bigEndian) ReadUint56(data []byte, x *uint64) ([]byte, bool) {
+	return readUintBE(data, x, 7)
+}
+
+// WriteUint40 writes 40 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as little endian.
+func (littleEndian) WriteUint40(data []byte, x uint64) []byte {
+	return writeUintLE(data, x, 5)
+}
+
+// WriteUint48 writes 48 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as little endian.
+func (littleEndian) WriteUint48(data []byte, x uint64) []byte {
+	return writeUintLE(data, x, 6)
+}
+
+// WriteUint56 writes 56 least significant bits of x to specified
+// slice and returns the resulting slice.
+//
+// Value is treated as little endian.
+func (littleEndian) WriteUint56(data []byte, x uint64) []byte {
+	return writeUintLE(data, x, 7)
+}
+
+// ReadUint40 reads a 40-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (littleEndian) ReadUint40(data []byte, x *uint64) ([]byte, bool) {
+	return readUintLE(data, x, 5)
+}
+
+// ReadUint48 reads a 48-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (littleEndian) ReadUint48(data []byte, x *uint64) ([]byte, bool) {
+	return readUintLE(data, x, 6)
+}
+
+// ReadUint56 reads a 56-bit value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (littleEndian) ReadUint56(data []byte, x *uint64) ([]byte, bool) {
+	return readUintLE(data, x, 7)
+}
+
+// WriteInt8 writes a Int8 value to specified slice and returns the
+// resulting slice. It is the same for any endianness.
+func (bigEndian) WriteInt8(data []byte, x int8) []byte {
+	return WriteInt8(data, x)
+}
+
+// ReadInt8 reads a Int8 value from specified slice and returns the
+// resulting slice and boolean flag telling if it was a success. It is
+// the same for any endianness.
+func (bigEndian) ReadInt8(data []byte, x *int8) ([]byte, bool) {
+	return ReadInt8(data, x)
+}
+
+// WriteInt16 writes a Int16 value to specified slice and returns
+// the resulting slice.
+//
+// Value is treated as big endian.
+func (e bigEndian) WriteInt16(data []byte, x int16) []byte {
+	return e.WriteUint16(data, uint16(x))
+}
+
+// WriteInt32 writes a Int32 value to specified slice and returns
+// the resulting slice.
+//
+// Value is treated as big endian.
+func (e bigEndian) WriteInt32(data []byte, x int32) []byte {
+	return e.WriteUint32(data, uint32(x))
+}
+
+// ReadInt16 reads a Int16 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (e bigEndian) ReadInt16(data []byte, x *int16) ([]byte, bool) {
+	var y uint16
+	data, ok := e.ReadUint16(data, &y)
+	if ok {
+		*x = int16(y)
+	}
+	return data, ok
+}
+
+// ReadInt32 reads a Int32 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (e bigEndian) ReadInt32(data []byte, x *int32) ([]byte, bool) {
+	var y uint32
+	data, ok := e.ReadUint32(data, &y)
+	if ok {
+		*x = int32(y)
+	}
+	return data, ok
+}
+
+// ReadInt64 reads a Int64 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (e bigEndian) ReadInt64(data []byte, x *int64) ([]byte, bool) {
+	var y uint64
+	data, ok := e.ReadUint64(data, &y)
+	if ok {
+		*x = int64(y)
+	}
+	return data, ok
+}
+
+// WriteFloat32 writes a Float32 value to specified slice and
+// returns the resulting slice.
+//
+// Value is treated as big endian.
+func (e bigEndian) WriteFloat32(data []byte, x float32) []byte {
+	return e.WriteUint32(data, math.Float32bits(x))
+}
+
+// ReadFloat32 reads a Float32 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (e bigEndian) ReadFloat32(data []byte, x *float32) ([]byte, bool) {
+	var y uint32
+	data, ok := e.ReadUint32(data, &y)
+	if ok {
+		*x = math.Float32frombits(y)
+	}
+	return data, ok
+}
+
+// WriteFloat64 writes a Float64 value to specified slice and
+// returns the resulting slice.
+//
+// Value is treated as big endian.
+func (e bigEndian) WriteFloat64(data []byte, x float64) []byte {
+	return e.WriteUint64(data, math.Float64bits(x))
+}
+
+// ReadFloat64 reads a Float64 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as big endian.
+func (e bigEndian) ReadFloat64(data []byte, x *float64) ([]byte, bool) {
+	var y uint64
+	data, ok := e.ReadUint64(data, &y)
+	if ok {
+		*x = math.Float64frombits(y)
+	}
+	return data, ok
+}
+
+// WriteInt8 writes a Int8 value to specified slice and returns the
+// resulting slice. It is the same for any endianness.
+func (littleEndian) WriteInt8(data []byte, x int8) []byte {
+	return WriteInt8(data, x)
+}
+
+// ReadInt8 reads a Int8 value from specified slice and returns the
+// resulting slice and boolean flag telling if it was a success. It is
+// the same for any endianness.
+func (littleEndian) ReadInt8(data []byte, x *int8) ([]byte, bool) {
+	return ReadInt8(data, x)
+}
+
+// WriteInt16 writes a Int16 value to specified slice and returns
+// the resulting slice.
+//
+// Value is treated as little endian.
+func (e littleEndian) WriteInt16(data []byte, x int16) []byte {
+	return e.WriteUint16(data, uint16(x))
+}
+
+// WriteInt32 writes a Int32 value to specified slice and returns
+// the resulting slice.
+//
+// Value is treated as little endian.
+func (e littleEndian) WriteInt32(data []byte, x int32) []byte {
+	return e.WriteUint32(data, uint32(x))
+}
+
+// ReadInt16 reads a Int16 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (e littleEndian) ReadInt16(data []byte, x *int16) ([]byte, bool) {
+	var y uint16
+	data, ok := e.ReadUint16(data, &y)
+	if ok {
+		*x = int16(y)
+	}
+	return data, ok
+}
+
+// ReadInt32 reads a Int32 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (e littleEndian) ReadInt32(data []byte, x *int32) ([]byte, bool) {
+	var y uint32
+	data, ok := e.ReadUint32(data, &y)
+	if ok {
+		*x = int32(y)
+	}
+	return data, ok
+}
+
+// ReadInt64 reads a Int64 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (e littleEndian) ReadInt64(data []byte, x *int64) ([]byte, bool) {
+	var y uint64
+	data, ok := e.ReadUint64(data, &y)
+	if ok {
+		*x = int64(y)
+	}
+	return data, ok
+}
+
+// WriteFloat32 writes a Float32 value to specified slice and
+// returns the resulting slice.
+//
+// Value is treated as little endian.
+func (e littleEndian) WriteFloat32(data []byte, x float32) []byte {
+	return e.WriteUint32(data, math.Float32bits(x))
+}
+
+// ReadFloat32 reads a Float32 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (e littleEndian) ReadFloat32(data []byte, x *float32) ([]byte, bool) {
+	var y uint32
+	data, ok := e.ReadUint32(data, &y)
+	if ok {
+		*x = math.Float32frombits(y)
+	}
+	return data, ok
+}
+
+// WriteFloat64 writes a Float64 value to specified slice and
+// returns the resulting slice.
+//
+// Value is treated as little endian.
+func (e littleEndian) WriteFloat64(data []byte, x float64) []byte {
+	return e.WriteUint64(data, math.Float64bits(x))
+}
+
+// ReadFloat64 reads a Float64 value from specified slice and returns
+// the resulting slice and boolean flag telling if it was a success.
+//
+// Value is treated as little endian.
+func (e littleEndian) ReadFloat64(data []byte, x *float64) ([]byte, bool) {
+	var y uint64
+	data, ok := e.ReadUint64(data, &y)
+	if ok {
+		*x = math.Float64frombits(y)
+	}
+	return data, ok
+}
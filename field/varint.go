@@ -0,0 +1,125 @@
+package field
+
+import (
+	"encoding/binary"
+)
+
+// WriteUvarint appends unsigned LEB128 encoded x to data and returns
+// the resulting slice. The encoding is the same as of protobuf
+// varints and binary.PutUvarint.
+func WriteUvarint(data []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(data, buf[:n]...)
+}
+
+// ReadUvarint reads unsigned LEB128 encoded integer from the top of
+// data and puts it into x. Returns the resulting slice and true if
+// reading was ok. False is returned if data is truncated or the value
+// overflows 64 bits.
+func ReadUvarint(data []byte, x *uint64) ([]byte, bool) {
+	y, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, false
+	}
+
+	*x = y
+	return data[n:], true
+}
+
+// WriteVarint appends zigzag LEB128 encoded x to data and returns the
+// resulting slice. Zigzag encoding maps signed integers to unsigned
+// ones so that numbers of small magnitude have short encoding, e.g.
+// -1 is encoded as 1, 1 is encoded as 2 etc.
+func WriteVarint(data []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], x)
+	return append(data, buf[:n]...)
+}
+
+// ReadVarint reads zigzag LEB128 encoded integer from the top of data
+// and puts it into x. Returns the resulting slice and true if reading
+// was ok. False is returned if data is truncated or the value
+// overflows 64 bits.
+func ReadVarint(data []byte, x *int64) ([]byte, bool) {
+	y, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, false
+	}
+
+	*x = y
+	return data[n:], true
+}
+
+// MaxQUICVarint is the maximum value of QUIC variable-length integer.
+const MaxQUICVarint = 1<<62 - 1
+
+// QUICVarintLen returns the length of QUIC variable-length integer
+// encoding x in bytes, or 0 if x exceeds MaxQUICVarint.
+func QUICVarintLen(x uint64) int {
+	switch {
+	case x < 1<<6:
+		return 1
+	case x < 1<<14:
+		return 2
+	case x < 1<<30:
+		return 4
+	case x <= MaxQUICVarint:
+		return 8
+	}
+	return 0
+}
+
+// WriteQUICVarint appends x encoded as QUIC variable-length integer
+// (RFC 9000, section 16) to data. The shortest encoding is used.
+// Returns the resulting slice and true if x does not exceed
+// MaxQUICVarint, otherwise data is returned unchanged and false.
+func WriteQUICVarint(data []byte, x uint64) ([]byte, bool) {
+	switch QUICVarintLen(x) {
+	case 1:
+		return append(data, byte(x)), true
+	case 2:
+		return BigEndian.WriteUint16(data, uint16(x)|0x4000), true
+	case 4:
+		return BigEndian.WriteUint32(data, uint32(x)|0x80000000), true
+	case 8:
+		return BigEndian.WriteUint64(data, x|0xc000000000000000), true
+	}
+	return data, false
+}
+
+// ReadQUICVarint reads QUIC variable-length integer from the top of
+// data and puts it into x. Returns the resulting slice and true if
+// reading was ok. Non-shortest encodings are accepted as allowed by
+// RFC 9000.
+func ReadQUICVarint(data []byte, x *uint64) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	n := 1 << (data[0] >> 6)
+	if len(data) < n {
+		return nil, false
+	}
+
+	y := uint64(data[0] & 0x3f)
+	for _, b := range data[1:n] {
+		y = y<<8 | uint64(b)
+	}
+
+	*x = y
+	return data[n:], true
+}
+
+// ReadBERDefiniteLength reads BER encoded length from the top of data
+// and puts it into n. Unlike ReadBERLength the indefinite form is
+// rejected. Returns the resulting slice and true if reading was ok.
+// Use WriteBERLength with non-negative n to write definite length.
+func ReadBERDefiniteLength(data []byte, n *int) ([]byte, bool) {
+	var x int
+	if data, ok := ReadBERLength(data, &x); ok && x != IndefiniteLength {
+		*n = x
+		return data, true
+	}
+	return nil, false
+}
@@ -0,0 +1,118 @@
+package field
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestUvarint(t *testing.T) {
+	for _, c := range []struct {
+		x    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{300, []byte{0xac, 0x02}},
+		{math.MaxUint64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	} {
+		data := WriteUvarint([]byte{0xaa}, c.x)
+		assert(t, bytes.Equal(data[1:], c.want), c.x, data)
+
+		var x uint64
+		res, ok := ReadUvarint(append(c.want, 0xbb), &x)
+		assert(t, ok && x == c.x, c.x, x)
+		assert(t, bytes.Equal(res, []byte{0xbb}))
+
+		x = 1
+		_, ok = ReadUvarint(c.want[:len(c.want)-1], &x)
+		assert(t, !ok && x == 1, c.x)
+	}
+
+	// overflow
+	var x uint64
+	_, ok := ReadUvarint([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}, &x)
+	assert(t, !ok)
+	_, ok = ReadUvarint([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, &x)
+	assert(t, !ok)
+}
+
+func TestVarint(t *testing.T) {
+	for _, c := range []struct {
+		x    int64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{-1, []byte{0x01}},
+		{1, []byte{0x02}},
+		{-64, []byte{0x7f}},
+		{64, []byte{0x80, 0x01}},
+		{math.MinInt64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	} {
+		data := WriteVarint(nil, c.x)
+		assert(t, bytes.Equal(data, c.want), c.x, data)
+
+		var x int64
+		res, ok := ReadVarint(data, &x)
+		assert(t, ok && x == c.x && len(res) == 0, c.x, x)
+
+		_, ok = ReadVarint(data[:len(data)-1], &x)
+		assert(t, !ok)
+	}
+}
+
+func TestQUICVarint(t *testing.T) {
+	// examples from RFC 9000, appendix A.1
+	for _, c := range []struct {
+		x    uint64
+		want []byte
+	}{
+		{37, []byte{0x25}},
+		{15293, []byte{0x7b, 0xbd}},
+		{494878333, []byte{0x9d, 0x7f, 0x3e, 0x7d}},
+		{151288809941952652, []byte{0xc2, 0x19, 0x7c, 0x5e, 0xff, 0x14, 0xe8, 0x8c}},
+		{MaxQUICVarint, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	} {
+		assert(t, QUICVarintLen(c.x) == len(c.want), c.x)
+
+		data, ok := WriteQUICVarint([]byte{0xaa}, c.x)
+		assert(t, ok && bytes.Equal(data[1:], c.want), c.x, data)
+
+		var x uint64
+		res, ok := ReadQUICVarint(append(c.want, 0xbb), &x)
+		assert(t, ok && x == c.x, c.x, x)
+		assert(t, bytes.Equal(res, []byte{0xbb}))
+
+		x = 1
+		_, ok = ReadQUICVarint(c.want[:len(c.want)-1], &x)
+		assert(t, !ok && x == 1, c.x)
+	}
+
+	// non-shortest encoding
+	var x uint64
+	res, ok := ReadQUICVarint([]byte{0x40, 0x25}, &x)
+	assert(t, ok && x == 37 && len(res) == 0)
+
+	assert(t, QUICVarintLen(MaxQUICVarint+1) == 0)
+	data, ok := WriteQUICVarint([]byte{0xaa}, MaxQUICVarint+1)
+	assert(t, !ok && bytes.Equal(data, []byte{0xaa}), data)
+}
+
+func TestBERDefiniteLength(t *testing.T) {
+	var n int
+	res, ok := ReadBERDefiniteLength([]byte{0x82, 0x01, 0x00, 0xaa}, &n)
+	assert(t, ok && n == 0x100)
+	assert(t, bytes.Equal(res, []byte{0xaa}))
+
+	n = 1
+	_, ok = ReadBERDefiniteLength([]byte{0x80}, &n)
+	assert(t, !ok && n == 1)
+
+	_, ok = ReadBERDefiniteLength([]byte{0x82, 0x01}, &n)
+	assert(t, !ok && n == 1)
+
+	_, ok = ReadBERDefiniteLength([]byte{0x85, 1, 2, 3, 4, 5}, &n)
+	assert(t, !ok && n == 1)
+}
@@ -5,4 +5,5 @@ go 1.13
 require (
 	github.com/mediocregopher/radix.v2 v0.0.0-20181115013041-b67df6e626f9
 	golang.org/x/sys v0.0.0-20190913121621-c3b328c6e5a7
+	golang.org/x/text v0.3.6
 )